@@ -0,0 +1,230 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds user settings loaded from the config file
+type Config struct {
+	// Pager is the shell command the current diff is piped into (e.g. "delta | less -R").
+	// Empty means $PAGER, falling back to "less -R".
+	Pager string `json:"pager"`
+
+	// DefaultDisplayMode is the mode single-file mode opens in: "diff", "ctx", "full" or "blame"
+	DefaultDisplayMode string `json:"defaultDisplayMode"`
+
+	// DisplayModes overrides DefaultDisplayMode per file extension (e.g. {".md": "full"})
+	DisplayModes map[string]string `json:"displayModes"`
+
+	// DiffRenderer renders diffs with "builtin" (default) or "delta" when it is installed;
+	// x switches between them at runtime
+	DiffRenderer string `json:"diffRenderer"`
+
+	// ScrollToFirstChange scrolls newly loaded content to its first change: "full" (default)
+	// in full-file mode only, "all" in diffs too (past the commit description), or "off"
+	ScrollToFirstChange string `json:"scrollToFirstChange"`
+
+	// DiffColors overrides the colors of the built-in diff renderer
+	DiffColors DiffColors `json:"diffColors"`
+
+	// JoinNearbyHunks shows hunks separated by no more unchanged lines than their context
+	// as one block, with the lines between them, instead of under separate headers
+	JoinNearbyHunks bool `json:"joinNearbyHunks"`
+
+	// Gutter is the diff line number layout: "both" (default), "new-only", "old-only", "right" or "none"
+	Gutter string `json:"gutter"`
+
+	// PathTruncation is how long paths are shortened in the file list:
+	// "keep-basename" (default), "leading", "basename-only" or "start"
+	PathTruncation string `json:"pathTruncation"`
+
+	// TreeExpandDepth is how many directory levels the file tree opens expanded.
+	// Zero means the default of 1 (top-level directories only).
+	TreeExpandDepth int `json:"treeExpandDepth"`
+
+	// StartupView is what var opens on: "auto" (default) shows the working copy when it has
+	// uncommitted changes and the commits otherwise, "commits" or "changes" always open on one
+	StartupView string `json:"startupView"`
+
+	// DisableAutoRefresh stops polling HEAD for new commits made outside var
+	DisableAutoRefresh bool `json:"disableAutoRefresh"`
+
+	// DisableTextconv shows files with a textconv diff driver (.gitattributes diff=driver)
+	// as git stores them instead of converted to text; T toggles it at runtime
+	DisableTextconv bool `json:"disableTextconv"`
+
+	// WindowTitle sets the terminal title to the repository, file and commit being viewed,
+	// restoring the previous title on exit
+	WindowTitle bool `json:"windowTitle"`
+
+	// GroupCommitsByDate starts with day headers ("Today", "Yesterday", ...) between
+	// the commits in the commit list; ctrl+t toggles them at runtime
+	GroupCommitsByDate bool `json:"groupCommitsByDate"`
+
+	// ShowCommitStats starts with each commit's added and deleted line counts ("+42 -13")
+	// at the end of its row in the commit list; $ toggles them at runtime
+	ShowCommitStats bool `json:"showCommitStats"`
+
+	// CommitFormat lays out the rows of the commit list from a template of {hash}, {date},
+	// {author} and {subject} placeholders (e.g. "{hash} {date} {author} {subject}").
+	// Empty means the hash and subject.
+	CommitFormat string `json:"commitFormat"`
+
+	// ShowCodeOwners lists each file's CODEOWNERS owners after it in the file list
+	ShowCodeOwners bool `json:"showCodeOwners"`
+
+	// GitPath is the git executable to run. Empty means git on PATH.
+	GitPath string `json:"gitPath"`
+
+	// GitArgs are global options passed to every git command, before the subcommand
+	// (e.g. ["-c", "core.quotepath=false"])
+	GitArgs []string `json:"gitArgs"`
+
+	// ConfirmQuitAfterStaging asks before quitting once var has staged or unstaged changes
+	ConfirmQuitAfterStaging bool `json:"confirmQuitAfterStaging"`
+}
+
+// DiffColors are the colors of the built-in diff renderer. Each is an ANSI color number,
+// "0" to "255", or a "#rrggbb" hex value for truecolor terminals; empty keeps the default.
+type DiffColors struct {
+	Added   string `json:"added"`   // Added lines, green by default
+	Removed string `json:"removed"` // Removed lines, red by default
+	Context string `json:"context"` // Unchanged lines, the terminal's color by default
+
+	// Backgrounds behind the changed words of a modified line, shown in reverse video by default
+	AddedHighlight   string `json:"addedHighlight"`
+	RemovedHighlight string `json:"removedHighlight"`
+}
+
+// Default returns the settings used when no config file exists
+func Default() Config {
+	return Config{}
+}
+
+// Path returns the location of the config file ($XDG_CONFIG_HOME/var/config.json,
+// falling back to ~/.config/var/config.json)
+func Path() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "var", "config.json"), nil
+}
+
+// Load reads the config file, returning defaults if it does not exist
+func Load() (Config, error) {
+	cfg := Default()
+
+	path, err := Path()
+	if err != nil {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// PagerCommand returns the shell command used to page diffs
+func (c Config) PagerCommand() string {
+	if c.Pager != "" {
+		return c.Pager
+	}
+	if pager := os.Getenv("PAGER"); pager != "" {
+		return pager
+	}
+	return "less -R"
+}
+
+// DisplayModeFor returns the configured display mode name for a file,
+// falling back to DefaultDisplayMode for unmatched extensions
+func (c Config) DisplayModeFor(path string) string {
+	ext := strings.ToLower(filepath.Ext(path))
+	for key, mode := range c.DisplayModes {
+		key = strings.ToLower(key)
+		if !strings.HasPrefix(key, ".") {
+			key = "." + key
+		}
+		if ext != "" && key == ext {
+			return mode
+		}
+	}
+	return c.DefaultDisplayMode
+}
+
+// TreeExpandDepthOrDefault returns the configured tree expand depth, defaulting to 1
+func (c Config) TreeExpandDepthOrDefault() int {
+	if c.TreeExpandDepth <= 0 {
+		return 1
+	}
+	return c.TreeExpandDepth
+}
+
+// State holds what var remembers between runs, kept apart from the user's config file
+type State struct {
+	// SidebarRatio is the share of the width given to the left column; zero means the default
+	SidebarRatio float64 `json:"sidebarRatio"`
+}
+
+// StatePath returns the location of the state file ($XDG_STATE_HOME/var/state.json,
+// falling back to ~/.local/state/var/state.json)
+func StatePath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "var", "state.json"), nil
+}
+
+// LoadState reads the state file, returning an empty state if it is missing or unreadable
+func LoadState() State {
+	var state State
+	path, err := StatePath()
+	if err != nil {
+		return state
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return state
+	}
+	json.Unmarshal(data, &state)
+	return state
+}
+
+// SaveState writes the state file
+func SaveState(state State) error {
+	path, err := StatePath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GetAuthorLines returns the numbers of the lines of a file, as it is at the end of rev,
+// that blame attributes to author (a case-insensitive part of their name or email). rev
+// is a commit, counting its whole history, or a range like v1.0..v1.1, counting only
+// the commits in it.
+func (s *Service) GetAuthorLines(filePath, rev, author string) ([]int, error) {
+	if strings.HasPrefix(rev, "-") {
+		return nil, fmt.Errorf("invalid revision %s", rev)
+	}
+	// --root keeps the root commit's lines, which blame otherwise marks as boundary
+	output, err := s.runGit("blame", "--line-porcelain", "--root", rev, "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	author = strings.ToLower(author)
+	var lines []int
+	var line int
+	var name, mail string
+	boundary := false
+	for _, row := range strings.Split(string(output), "\n") {
+		switch {
+		case strings.HasPrefix(row, "\t"):
+			// The line's content ends its entry
+			if !boundary && (strings.Contains(strings.ToLower(name), author) || strings.Contains(strings.ToLower(mail), author)) {
+				lines = append(lines, line)
+			}
+			boundary = false
+		case strings.HasPrefix(row, "author "):
+			name = strings.TrimPrefix(row, "author ")
+		case strings.HasPrefix(row, "author-mail "):
+			mail = strings.TrimPrefix(row, "author-mail ")
+		case row == "boundary":
+			// Older than the range, so not one of its changes
+			boundary = true
+		default:
+			// An entry starts with "<hash> <original line> <final line> [<group size>]"
+			if fields := strings.Fields(row); len(fields) >= 3 && len(fields[0]) >= 40 {
+				line, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return lines, nil
+}
@@ -0,0 +1,97 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PRView is a branch's changes since it diverged from its base, as a pull request shows them
+type PRView struct {
+	Base    string
+	Branch  string
+	Commits []Commit     // Commits on the branch but not the base (base..branch), newest first
+	Files   []FileStatus // Files changed since the merge base (base...branch)
+}
+
+// GetHeadBranch returns the branch HEAD is on, or an empty string when HEAD is detached
+func (s *Service) GetHeadBranch() (string, error) {
+	output, err := s.runGit("symbolic-ref", "-q", "--short", "HEAD")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// -q exits 1 without a message when HEAD is not a symbolic ref
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDefaultBranch returns the repository's main branch: origin's HEAD if known,
+// otherwise a local main or master
+func (s *Service) GetDefaultBranch() (string, error) {
+	if output, err := s.runGit("symbolic-ref", "--short", "refs/remotes/origin/HEAD"); err == nil {
+		return strings.TrimSpace(string(output)), nil
+	}
+	for _, name := range []string{"main", "master"} {
+		if s.refExists(name) {
+			return name, nil
+		}
+	}
+	return "", fmt.Errorf("no default branch found; use base...branch")
+}
+
+// refExists reports whether name resolves to a commit
+func (s *Service) refExists(name string) bool {
+	if strings.HasPrefix(name, "-") {
+		return false
+	}
+	_, err := s.runGit("rev-parse", "--verify", "--quiet", name+"^{commit}")
+	return err == nil
+}
+
+// GetBranchPRView returns the commits and files branch adds on top of base
+func (s *Service) GetBranchPRView(base, branch string) (*PRView, error) {
+	for _, ref := range []string{base, branch} {
+		if !s.refExists(ref) {
+			return nil, fmt.Errorf("unknown branch %s", ref)
+		}
+	}
+
+	output, err := s.runGit(s.limitPaths("log", commitLogFormat, base+".."+branch, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	view := &PRView{Base: base, Branch: branch, Commits: parseCommitLog(string(output))}
+
+	output, err = s.runGit(s.limitPaths("diff", "--name-status", base+"..."+branch, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	view.Files = parseNameStatus(string(output))
+	return view, nil
+}
+
+// GetPRFileDiff returns a file's diff between the merge base of base and branch, and branch
+func (s *Service) GetPRFileDiff(base, branch, filePath string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--color=always", base+"..."+branch, "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// GetBranchDivergence returns how many commits branch has that base lacks (ahead)
+// and base has that branch lacks (behind)
+func (s *Service) GetBranchDivergence(branch, base string) (ahead, behind int, err error) {
+	output, err := s.runGit("rev-list", "--left-right", "--count", branch+"..."+base, "--")
+	if err != nil {
+		return 0, 0, err
+	}
+	if _, err := fmt.Sscanf(string(output), "%d\t%d", &ahead, &behind); err != nil {
+		return 0, 0, fmt.Errorf("unexpected rev-list output %q", strings.TrimSpace(string(output)))
+	}
+	return ahead, behind, nil
+}
@@ -0,0 +1,129 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// CodeOwners maps paths to their owners using a CODEOWNERS file
+type CodeOwners struct {
+	Path  string // Location of the file relative to the repository root
+	rules []ownerRule
+}
+
+type ownerRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// codeOwnersLocations are searched in order, as GitHub does
+var codeOwnersLocations = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// LoadCodeOwners reads the repository's CODEOWNERS file, returning nil if there is none
+func (s *Service) LoadCodeOwners() (*CodeOwners, error) {
+	for _, loc := range codeOwnersLocations {
+		data, err := os.ReadFile(filepath.Join(s.repoPath, loc))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		owners := parseCodeOwners(string(data))
+		owners.Path = loc
+		return owners, nil
+	}
+	return nil, nil
+}
+
+// parseCodeOwners reads "pattern owner..." lines, skipping comments and GitLab section headers
+func parseCodeOwners(content string) *CodeOwners {
+	owners := &CodeOwners{}
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") ||
+			strings.HasPrefix(fields[0], "[") || strings.HasPrefix(fields[0], "^[") {
+			continue
+		}
+		owners.rules = append(owners.rules, ownerRule{
+			pattern: compileOwnerPattern(fields[0]),
+			owners:  fields[1:],
+		})
+	}
+	return owners
+}
+
+// compileOwnerPattern turns a gitignore-style pattern into a regexp matching the paths it
+// covers: a pattern with a slash is relative to the root, one without matches at any
+// depth, and a match on a directory covers everything inside it. A wildcard in the last
+// segment matches only that level, so docs/* covers the files directly in docs.
+func compileOwnerPattern(pattern string) *regexp.Regexp {
+	anchored := strings.Contains(strings.TrimSuffix(pattern, "/"), "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+	last := pattern[strings.LastIndex(pattern, "/")+1:]
+	wildLast := strings.ContainsAny(last, "*?")
+
+	var expr strings.Builder
+	if anchored {
+		expr.WriteString("^")
+	} else {
+		expr.WriteString("^(?:.*/)?")
+	}
+	for i := 0; i < len(pattern); i++ {
+		switch {
+		case strings.HasPrefix(pattern[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(pattern[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case pattern[i] == '*':
+			expr.WriteString("[^/]*")
+		case pattern[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(pattern[i : i+1]))
+		}
+	}
+	switch {
+	case dirOnly:
+		expr.WriteString("/.*$")
+	case wildLast:
+		expr.WriteString("$")
+	default:
+		expr.WriteString("(?:/.*)?$")
+	}
+	return regexp.MustCompile(expr.String())
+}
+
+// Owners returns the owners of path from the last matching rule, nil if it has none
+func (c *CodeOwners) Owners(path string) []string {
+	if c == nil {
+		return nil
+	}
+	for i := len(c.rules) - 1; i >= 0; i-- {
+		if c.rules[i].pattern.MatchString(path) {
+			return c.rules[i].owners
+		}
+	}
+	return nil
+}
+
+// IsOwnedBy reports whether owner (@user, @org/team or an email, the @ optional) owns path
+func (c *CodeOwners) IsOwnedBy(path, owner string) bool {
+	owner = strings.TrimPrefix(owner, "@")
+	for _, o := range c.Owners(path) {
+		if strings.EqualFold(strings.TrimPrefix(o, "@"), owner) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package git
+
+import "testing"
+
+func TestCompileOwnerPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		matches []string
+		misses  []string
+	}{
+		{"*", []string{"a.go", "docs/a.md"}, nil},
+		{"*.go", []string{"main.go", "internal/git/run.go"}, []string{"main.go.orig", "go.mod"}},
+		{"/docs/", []string{"docs/a.md", "docs/a/b.md"}, []string{"docs", "src/docs/a.md"}},
+		{"docs/", []string{"docs/a.md", "docs/a/b.md"}, []string{"docs"}},
+		{"docs/*", []string{"docs/a.md"}, []string{"docs/a/b.md", "src/docs/a.md"}},
+		{"docs/**", []string{"docs/a.md", "docs/a/b.md"}, []string{"src/docs/a.md"}},
+		{"docs", []string{"docs", "docs/a/b.md", "src/docs/a.md"}, []string{"docsite/a.md"}},
+		{"/build/logs", []string{"build/logs", "build/logs/today.log"}, []string{"src/build/logs"}},
+		{"**/x", []string{"x", "a/x", "a/b/x", "a/x/y.go"}, []string{"ax", "a/xy"}},
+		{"a/**/b", []string{"a/b", "a/c/b", "a/c/d/b", "a/c/b/e.go"}, []string{"b", "x/a/c/b", "a/cb"}},
+		{"a?.md", []string{"ab.md", "docs/ac.md"}, []string{"a.md", "a/b.md", "abc.md"}},
+	}
+	for _, tt := range tests {
+		re := compileOwnerPattern(tt.pattern)
+		for _, path := range tt.matches {
+			if !re.MatchString(path) {
+				t.Errorf("pattern %q (%s) does not match %q", tt.pattern, re, path)
+			}
+		}
+		for _, path := range tt.misses {
+			if re.MatchString(path) {
+				t.Errorf("pattern %q (%s) matches %q", tt.pattern, re, path)
+			}
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// commandLogSize is how many of the latest commands the log keeps
+const commandLogSize = 500
+
+// CommandRecord is a command the service ran
+type CommandRecord struct {
+	Args     []string // The command line, starting with the executable's base name
+	Start    time.Time
+	Duration time.Duration
+	ExitCode int    // -1 when the command couldn't start or was killed
+	Err      string // Why the command failed (its stderr when it has any), empty on success
+}
+
+// commandLog is a ring buffer of the latest commands
+type commandLog struct {
+	mu      sync.Mutex
+	records []CommandRecord
+	next    int // Slot the next record goes in once the buffer is full
+}
+
+func (l *commandLog) add(r CommandRecord) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.records) < commandLogSize {
+		l.records = append(l.records, r)
+		return
+	}
+	l.records[l.next] = r
+	l.next = (l.next + 1) % commandLogSize
+}
+
+// recordCommand logs a finished command with its timing and outcome
+func (s *Service) recordCommand(cmd *exec.Cmd, start time.Time, err error) {
+	if s.unlogged {
+		return
+	}
+	args := slices.Clone(cmd.Args)
+	args[0] = filepath.Base(args[0])
+	r := CommandRecord{Args: args, Start: start, Duration: time.Since(start)}
+	if err != nil {
+		r.ExitCode = -1
+		r.Err = err.Error()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			r.ExitCode = exitErr.ExitCode()
+			if stderr := strings.TrimSpace(string(exitErr.Stderr)); stderr != "" {
+				r.Err = stderr
+			}
+		}
+	}
+	s.commands.add(r)
+}
+
+// Commands returns the latest commands the service ran, oldest first
+func (s *Service) Commands() []CommandRecord {
+	l := s.commands
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Concat(l.records[l.next:], l.records[:l.next])
+}
@@ -0,0 +1,94 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// CommitStat is how many lines a commit adds and deletes
+type CommitStat struct {
+	Additions int
+	Deletions int
+}
+
+// shortstatRegex reads the counts of a --shortstat line, either of which git leaves
+// out when it is zero: " 3 files changed, 42 insertions(+), 13 deletions(-)"
+var shortstatRegex = regexp.MustCompile(`(?:(\d+) insertions?\(\+\))?(?:, )?(?:(\d+) deletions?\(-\))?$`)
+
+// GetCommitStats returns the lines each of the given commits adds and deletes, from one
+// git log over all of them. Merges, which log shows no diff for, count as zero, and
+// names that aren't hashes are left out.
+func (s *Service) GetCommitStats(hashes []string) (map[string]CommitStat, error) {
+	args := []string{"log", "--no-walk=unsorted", "--format=%x00%H", "--shortstat"}
+	for _, hash := range hashes {
+		if isHash(hash) {
+			args = append(args, hash)
+		}
+	}
+	if len(args) == 4 {
+		return map[string]CommitStat{}, nil
+	}
+	output, err := s.runGit(append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitStats(string(output), hashes), nil
+}
+
+// parseCommitStats reads the --shortstat log of GetCommitStats into the stats of the
+// given hashes. log prints full hashes and the list's are abbreviated, so they are
+// matched by prefix.
+func parseCommitStats(output string, hashes []string) map[string]CommitStat {
+	stats := make(map[string]CommitStat, len(hashes))
+	asked := make(map[string]bool, len(hashes))
+	lengths := map[int]bool{}
+	for _, hash := range hashes {
+		asked[hash] = true
+		lengths[len(hash)] = true
+	}
+	wanted := func(full string) []string {
+		var names []string
+		for n := range lengths {
+			if n <= len(full) && asked[full[:n]] {
+				names = append(names, full[:n])
+			}
+		}
+		return names
+	}
+
+	var current []string
+	for _, line := range strings.Split(output, "\n") {
+		if full, ok := strings.CutPrefix(line, "\x00"); ok {
+			current = wanted(full)
+			for _, name := range current {
+				stats[name] = CommitStat{}
+			}
+			continue
+		}
+		m := shortstatRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil || !strings.Contains(line, "changed") {
+			continue
+		}
+		var stat CommitStat
+		stat.Additions, _ = strconv.Atoi(m[1])
+		stat.Deletions, _ = strconv.Atoi(m[2])
+		for _, name := range current {
+			stats[name] = stat
+		}
+	}
+	return stats
+}
+
+// isHash reports whether name is a full or abbreviated commit hash
+func isHash(name string) bool {
+	if len(name) < 4 {
+		return false
+	}
+	for _, r := range name {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
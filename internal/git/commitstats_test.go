@@ -0,0 +1,53 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShortstatRegex(t *testing.T) {
+	tests := []struct {
+		line                 string
+		additions, deletions string
+	}{
+		{"3 files changed, 42 insertions(+), 13 deletions(-)", "42", "13"},
+		{"1 file changed, 1 insertion(+), 1 deletion(-)", "1", "1"},
+		{"2 files changed, 7 insertions(+)", "7", ""},
+		{"1 file changed, 5 deletions(-)", "", "5"},
+		{"1 file changed, 1 deletion(-)", "", "1"},
+	}
+	for _, tt := range tests {
+		m := shortstatRegex.FindStringSubmatch(tt.line)
+		if m == nil {
+			t.Errorf("shortstatRegex didn't match %q", tt.line)
+			continue
+		}
+		if m[1] != tt.additions || m[2] != tt.deletions {
+			t.Errorf("shortstatRegex on %q = %q, %q; want %q, %q", tt.line, m[1], m[2], tt.additions, tt.deletions)
+		}
+	}
+}
+
+func TestParseCommitStats(t *testing.T) {
+	output := "\x00aaaa1111aaaa1111aaaa1111aaaa1111aaaa1111\n\n" +
+		" 3 files changed, 42 insertions(+), 13 deletions(-)\n" +
+		"\x00bbbb2222bbbb2222bbbb2222bbbb2222bbbb2222\n\n" +
+		" 1 file changed, 7 insertions(+)\n" +
+		"\x00cccc3333cccc3333cccc3333cccc3333cccc3333\n\n" +
+		" 1 file changed, 1 deletion(-)\n" +
+		"\x00dddd4444dddd4444dddd4444dddd4444dddd4444\n" +
+		"\x00eeee5555eeee5555eeee5555eeee5555eeee5555\n\n" +
+		" 2 files changed, 9 insertions(+), 2 deletions(-)\n"
+	// Abbreviations of different lengths, one commit asked for twice, and one not asked for
+	hashes := []string{"aaaa111", "bbbb2222bb", "cccc3333", "cccc333", "dddd4444"}
+	want := map[string]CommitStat{
+		"aaaa111":    {Additions: 42, Deletions: 13},
+		"bbbb2222bb": {Additions: 7},
+		"cccc3333":   {Deletions: 1},
+		"cccc333":    {Deletions: 1},
+		"dddd4444":   {},
+	}
+	if got := parseCommitStats(output, hashes); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseCommitStats = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GetArbitraryDiff diffs two files given as "path" (working tree) or "path@rev"
+// (the file at a revision), whether or not git relates the two paths
+func (s *Service) GetArbitraryDiff(specA, specB string) (string, error) {
+	pathA, revA := splitFileSpec(specA, s.refExists)
+	pathB, revB := splitFileSpec(specB, s.refExists)
+	if pathA == "" || pathB == "" {
+		return "", fmt.Errorf("expected path or path@rev")
+	}
+
+	if revA != "" && revB != "" {
+		output, err := s.runGit(s.convertText("diff", "--color=always", revA+":"+pathA, revB+":"+pathB)...)
+		if err != nil {
+			return "", err
+		}
+		return string(output), nil
+	}
+
+	// At least one side is in the working tree, so compare files on disk,
+	// writing out a revision's version to a temporary file
+	dir, err := os.MkdirTemp("", "var-compare-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	fileA, err := s.compareFile(dir, "a", pathA, revA)
+	if err != nil {
+		return "", err
+	}
+	fileB, err := s.compareFile(dir, "b", pathB, revB)
+	if err != nil {
+		return "", err
+	}
+
+	return s.diffNoIndex(fileA, fileB)
+}
+
+// splitFileSpec splits "path@rev" into the path and the revision, or returns the whole
+// spec as a working tree path. Paths can hold @ themselves (node_modules/@types,
+// icon@2x.png), and so can revisions (HEAD@{1}), so the split is at the last @ whose
+// suffix isRev accepts.
+func splitFileSpec(spec string, isRev func(string) bool) (string, string) {
+	for i := strings.LastIndex(spec, "@"); i > 0; i = strings.LastIndex(spec[:i], "@") {
+		if rev := spec[i+1:]; rev != "" && isRev(rev) {
+			return spec[:i], rev
+		}
+	}
+	return spec, ""
+}
+
+// diffNoIndex diffs two files on disk, with any extra options before the paths
+func (s *Service) diffNoIndex(args ...string) (string, error) {
+	paths := args[len(args)-2:]
+	args = append([]string{"diff", "--no-index", "--color=always"}, args[:len(args)-2]...)
+	output, err := s.runGit(s.convertText(append(args, "--", paths[0], paths[1])...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// --no-index exits 1 when the files differ
+		err = nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// GetCommitMessageDiff returns a diff of oldRef's commit message to newRef's, with the
+// whole message as context, or an empty string if the messages match
+func (s *Service) GetCommitMessageDiff(newRef, oldRef string) (string, error) {
+	oldMsg, err := s.runGit("log", "-1", "--format=%B", oldRef, "--")
+	if err != nil {
+		return "", err
+	}
+	newMsg, err := s.runGit("log", "-1", "--format=%B", newRef, "--")
+	if err != nil {
+		return "", err
+	}
+	// %B ends the message with a blank line
+	oldMsg = append(bytes.TrimRight(oldMsg, "\n"), '\n')
+	newMsg = append(bytes.TrimRight(newMsg, "\n"), '\n')
+	if bytes.Equal(oldMsg, newMsg) {
+		return "", nil
+	}
+
+	dir, err := os.MkdirTemp("", "var-message-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	oldPath := filepath.Join(dir, "old")
+	newPath := filepath.Join(dir, "new")
+	if err := os.WriteFile(oldPath, oldMsg, 0o644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(newPath, newMsg, 0o644); err != nil {
+		return "", err
+	}
+	context := bytes.Count(oldMsg, []byte("\n")) + bytes.Count(newMsg, []byte("\n"))
+	return s.diffNoIndex(fmt.Sprintf("-U%d", context), oldPath, newPath)
+}
+
+// compareFile returns a path on disk holding the file: the working tree copy when
+// rev is empty, otherwise the revision's version written under dir
+func (s *Service) compareFile(dir, side, path, rev string) (string, error) {
+	if rev == "" {
+		full := filepath.Join(s.repoPath, path)
+		if _, err := os.Stat(full); err != nil {
+			return "", err
+		}
+		return full, nil
+	}
+	content, err := s.runGit("show", rev+":"+path)
+	if err != nil {
+		return "", fmt.Errorf("%s not found at %s", path, rev)
+	}
+	out := filepath.Join(dir, side, filepath.Base(path))
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return "", err
+	}
+	return out, os.WriteFile(out, content, 0o644)
+}
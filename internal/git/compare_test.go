@@ -0,0 +1,29 @@
+package git
+
+import "testing"
+
+func TestSplitFileSpec(t *testing.T) {
+	revs := map[string]bool{"HEAD": true, "HEAD~3": true, "origin/main": true, "HEAD@{1}": true, "v1.0": true}
+	isRev := func(rev string) bool { return revs[rev] }
+	tests := []struct {
+		spec, path, rev string
+	}{
+		{"main.go", "main.go", ""},
+		{"main.go@HEAD~3", "main.go", "HEAD~3"},
+		{"internal/x.go@origin/main", "internal/x.go", "origin/main"},
+		{"icon@2x.png", "icon@2x.png", ""},
+		{"icon@2x.png@v1.0", "icon@2x.png", "v1.0"},
+		{"node_modules/@types/x/index.d.ts", "node_modules/@types/x/index.d.ts", ""},
+		{"node_modules/@types/x/index.d.ts@HEAD", "node_modules/@types/x/index.d.ts", "HEAD"},
+		{"a.go@HEAD@{1}", "a.go", "HEAD@{1}"},
+		{"a.go@unknown", "a.go@unknown", ""},
+		{"a.go@", "a.go@", ""},
+		{"@HEAD", "@HEAD", ""},
+	}
+	for _, tt := range tests {
+		path, rev := splitFileSpec(tt.spec, isRev)
+		if path != tt.path || rev != tt.rev {
+			t.Errorf("splitFileSpec(%q) = %q, %q; want %q, %q", tt.spec, path, rev, tt.path, tt.rev)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// fetchTimeout bounds a fetch, which can stall on a slow or unreachable remote
+const fetchTimeout = 2 * time.Minute
+
+// Fetch downloads ref, a branch or any ref such as refs/pull/123/head, from remote into
+// a remote-tracking ref, leaving local branches and the working tree alone. It returns
+// the tracking ref's name (origin/pull/123/head). Credential prompts are turned off, so
+// a remote that needs them fails instead of waiting for input.
+func (s *Service) Fetch(remote, ref string) (string, error) {
+	if remote == "" || ref == "" || strings.HasPrefix(remote, "-") || strings.HasPrefix(ref, "-") {
+		return "", fmt.Errorf("invalid remote or ref %q %q", remote, ref)
+	}
+	name := strings.TrimPrefix(strings.TrimPrefix(ref, "refs/heads/"), "refs/")
+	tracking := remote + "/" + name
+
+	cmd := s.gitCommand("fetch", "--no-tags", remote, "+"+ref+":refs/remotes/"+tracking)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	detachFromTerminal(cmd)
+	timer := time.AfterFunc(fetchTimeout, func() { killProcessGroup(cmd) })
+	_, err := s.run(cmd)
+	if !timer.Stop() {
+		return "", fmt.Errorf("fetching %s from %s timed out after %s", ref, remote, fetchTimeout)
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return "", fmt.Errorf("%s", fetchFailure(string(exitErr.Stderr)))
+	}
+	if err != nil {
+		return "", err
+	}
+	return tracking, nil
+}
+
+// fetchFailure picks the line saying why a fetch failed out of its stderr, which can
+// have progress before it and advice after it, as when ssh is refused
+func fetchFailure(stderr string) string {
+	lines := strings.Split(strings.TrimSpace(stderr), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "fatal: ") || strings.HasPrefix(line, "error: ") {
+			return line
+		}
+	}
+	return lines[len(lines)-1]
+}
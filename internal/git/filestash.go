@@ -0,0 +1,49 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetFileStashes returns the stashes that change filePath relative to the commit they
+// were made on, newest first, with each stash's selector (stash@{1}) as its Ref. Each
+// stash is diffed on its own, so this costs a git call per stash.
+func (s *Service) GetFileStashes(filePath string) ([]Commit, error) {
+	output, err := s.runGit("stash", "list", "--format=%h%x00%at%x00%gd%x00%gs")
+	if err != nil {
+		return nil, err
+	}
+
+	var stashes []Commit
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(line, "\x00", 4)
+		if len(parts) < 4 {
+			continue
+		}
+		if !s.stashChangesFile(parts[0], filePath) {
+			continue
+		}
+		stash := Commit{Hash: parts[0], Message: parts[2] + ": " + parts[3], Ref: parts[2]}
+		if ts, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			stash.Date = time.Unix(ts, 0)
+		}
+		stashes = append(stashes, stash)
+	}
+	return stashes, nil
+}
+
+// stashChangesFile reports whether a stash's working tree differs from its base for the file
+func (s *Service) stashChangesFile(stashHash, filePath string) bool {
+	_, err := s.runGit("diff", "--quiet", stashHash+"^1", stashHash, "--", filePath)
+	// --quiet exits 1 when there are differences
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 1
+}
+
+// IsStashRef reports whether a Ref is a stash's selector rather than HEAD's reflog's
+func IsStashRef(ref string) bool {
+	return strings.HasPrefix(ref, "stash@{")
+}
@@ -0,0 +1,27 @@
+package git
+
+import (
+	"fmt"
+	"slices"
+	"sync/atomic"
+)
+
+// hunkJoin holds whether diffs show nearby hunks as one
+type hunkJoin struct {
+	on atomic.Bool
+}
+
+// SetJoinNearbyHunks makes diffs join hunks separated by no more unchanged lines than
+// their context, showing the lines between them instead of a second hunk header
+func (s *Service) SetJoinNearbyHunks(on bool) {
+	s.hunks.on.Store(on)
+}
+
+// joinHunks adds --inter-hunk-context after the subcommand that starts args when
+// nearby hunks are joined
+func (s *Service) joinHunks(context int, args []string) []string {
+	if !s.hunks.on.Load() {
+		return args
+	}
+	return slices.Insert(args, 1, fmt.Sprintf("--inter-hunk-context=%d", context))
+}
@@ -0,0 +1,81 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// GetStagedFiles returns the files with changes staged in the index
+func (s *Service) GetStagedFiles() ([]FileStatus, error) {
+	output, err := s.runGit(s.limitPaths("diff", "--cached", "--name-status", "--")...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNameStatus(string(output)), nil
+}
+
+// GetStagedDiff returns the staged changes of a file relative to HEAD
+func (s *Service) GetStagedDiff(filePath string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--cached", "--color=always", "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// UnstageHunk removes a single staged hunk from the index, leaving the working tree untouched.
+// hunkText is the plain hunk starting at its @@ header.
+func (s *Service) UnstageHunk(filePath, hunkText string) error {
+	// Pin the prefixes so user diff settings can't break the patch
+	diff, err := s.runGit("diff", "--cached", "--no-color", "--no-ext-diff", "--src-prefix=a/", "--dst-prefix=b/", "--", filePath)
+	if err != nil {
+		return err
+	}
+	header, _, found := strings.Cut(string(diff), "\n@@")
+	if !found {
+		return fmt.Errorf("no staged changes in %s", filePath)
+	}
+
+	patch := header + "\n" + strings.TrimRight(hunkText, "\n") + "\n"
+	cmd := s.gitCommand("apply", "--cached", "--reverse", "-")
+	cmd.Stdin = strings.NewReader(patch)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if _, err := s.run(cmd); err != nil {
+		if stderr.Len() > 0 {
+			return fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+		}
+		return err
+	}
+	return nil
+}
+
+// StageFile adds a file's working tree changes, including its deletion, to the index
+func (s *Service) StageFile(filePath string) error {
+	return s.runIndexCommand("add", "--", filePath)
+}
+
+// UnstageFile resets a file in the index to HEAD, leaving the working tree untouched
+func (s *Service) UnstageFile(filePath string) error {
+	return s.runIndexCommand("restore", "--staged", "--", filePath)
+}
+
+// DiscardFile replaces a file's working tree changes with the index version, losing them
+func (s *Service) DiscardFile(filePath string) error {
+	return s.runIndexCommand("checkout", "--", filePath)
+}
+
+// runIndexCommand runs a command that changes the index or working tree, returning
+// git's own message when it fails
+func (s *Service) runIndexCommand(args ...string) error {
+	_, err := s.runGit(args...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// lfsPointerVersion is the first line of every Git LFS pointer file
+const lfsPointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+// LFSPointer holds the parsed fields of a Git LFS pointer file
+type LFSPointer struct {
+	OID  string // e.g. "sha256:4d7a..."
+	Size int64  // Size of the real object in bytes
+}
+
+// parseLFSPointer recognizes Git LFS pointer content
+func parseLFSPointer(content string) (LFSPointer, bool) {
+	if !strings.HasPrefix(content, lfsPointerVersion) {
+		return LFSPointer{}, false
+	}
+
+	var ptr LFSPointer
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "oid":
+			ptr.OID = value
+		case "size":
+			ptr.Size, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return ptr, ptr.OID != ""
+}
+
+// lfsObjectPresent reports whether the LFS object has been downloaded into the local store
+func (s *Service) lfsObjectPresent(oid string) bool {
+	hash := strings.TrimPrefix(oid, "sha256:")
+	if len(hash) < 4 {
+		return false
+	}
+	output, err := s.runGit("rev-parse", "--git-common-dir")
+	if err != nil {
+		return false
+	}
+	gitDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(s.repoPath, gitDir)
+	}
+	_, err = os.Stat(filepath.Join(gitDir, "lfs", "objects", hash[:2], hash[2:4], hash))
+	return err == nil
+}
+
+// formatLFSPointer renders a pointer as a readable summary instead of its raw text
+func (s *Service) formatLFSPointer(ptr LFSPointer) string {
+	location := "not downloaded (run git lfs fetch)"
+	if s.lfsObjectPresent(ptr.OID) {
+		location = "present locally"
+	}
+	return fmt.Sprintf("Git LFS pointer\n\n  oid:    %s\n  size:   %s (%d bytes)\n  object: %s\n",
+		ptr.OID, formatSize(ptr.Size), ptr.Size, location)
+}
+
+// formatSize renders a byte count with a binary unit suffix
+func formatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
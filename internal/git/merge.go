@@ -0,0 +1,26 @@
+package git
+
+// GetMergeFiles returns the files where a merge's result differs from every parent: the
+// conflicts it resolved and any changes of its own. Their status is the first parent's.
+func (s *Service) GetMergeFiles(commitHash string) ([]FileStatus, error) {
+	output, err := s.runGit(s.limitPaths("diff-tree", "--no-commit-id", "--cc", "--name-status", "-r", commitHash, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	files := parseNameStatus(string(output))
+	for i := range files {
+		// A combined status has a letter per parent ("MM")
+		files[i].Status = files[i].Status[:1]
+	}
+	return files, nil
+}
+
+// GetMergeResolution returns the combined diff of a file in a merge (git show --cc): each
+// line has a +/- column per parent, comparing the result with that parent
+func (s *Service) GetMergeResolution(commitHash, filePath string) (string, error) {
+	output, err := s.runGit(s.convertText("show", "--cc", "--format=", commitHash, "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,21 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// GetCommitNote returns the note attached to a commit under refs/notes/commits (git notes
+// add), empty if it has none
+func (s *Service) GetCommitNote(hash string) (string, error) {
+	output, err := s.runGit("notes", "show", hash)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "no note found") {
+			return "", nil
+		}
+		return "", err
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
@@ -0,0 +1,163 @@
+package git
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PatchFile is one file's section of a patch
+type PatchFile struct {
+	FileStatus
+	Additions int
+	Deletions int
+	Diff      string // The section's headers and hunks
+}
+
+// patchHunkRegex reads the line counts of a hunk header; a count is 1 when omitted
+var patchHunkRegex = regexp.MustCompile(`^@@ -\d+(?:,(\d+))? \+\d+(?:,(\d+))? @@`)
+
+// ParsePatch splits a unified diff, from git diff, git format-patch or diff -u, into its
+// files. Text outside the files' sections, like a format-patch message or signature, is dropped.
+func ParsePatch(content string) []PatchFile {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var files []PatchFile
+	var cur *PatchFile
+	var diff []string
+	oldLeft, newLeft := 0, 0 // Lines still to come in the current hunk
+	inHeader := false        // Between a section's start and its first hunk
+
+	flush := func() {
+		if cur != nil {
+			cur.Diff = strings.Join(diff, "\n")
+			files = append(files, *cur)
+		}
+		cur = nil
+		diff = nil
+	}
+	start := func(line string) {
+		flush()
+		cur = &PatchFile{FileStatus: FileStatus{Status: "M"}}
+		inHeader = true
+		if rest, ok := strings.CutPrefix(line, "diff --git "); ok {
+			// "a/old b/new": with equal paths, the middle splits them even if they contain spaces
+			if half := len(rest) / 2; len(rest)%2 == 1 && rest[half] == ' ' {
+				cur.Path = strings.TrimPrefix(rest[half+1:], "b/")
+			} else if i := strings.LastIndex(rest, " b/"); i >= 0 {
+				cur.Path = rest[i+3:]
+			}
+		}
+	}
+
+	for i, line := range lines {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(line, "-"):
+				oldLeft--
+				cur.Deletions++
+			case strings.HasPrefix(line, "+"):
+				newLeft--
+				cur.Additions++
+			case strings.HasPrefix(line, "\\"):
+			default:
+				// Context, whose leading space editors and mailers sometimes strip
+				oldLeft--
+				newLeft--
+			}
+			diff = append(diff, line)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "diff "):
+			start(line)
+			diff = append(diff, line)
+		case strings.HasPrefix(line, "--- ") && i+1 < len(lines) && strings.HasPrefix(lines[i+1], "+++ "):
+			if cur == nil || !inHeader {
+				// A plain unified diff, without "diff" lines between its files
+				start("")
+			}
+			if path := patchPath(line[4:]); path != "" {
+				cur.OldPath = path
+			}
+			diff = append(diff, line)
+		case strings.HasPrefix(line, "+++ ") && cur != nil && inHeader:
+			if path := patchPath(line[4:]); path != "" {
+				cur.Path = path
+			} else {
+				cur.Status = "D"
+			}
+			if strings.HasPrefix(diff[len(diff)-1], "--- /dev/null") {
+				cur.Status = "A"
+			}
+			diff = append(diff, line)
+		case strings.HasPrefix(line, "@@ ") && cur != nil:
+			m := patchHunkRegex.FindStringSubmatch(line)
+			if m == nil {
+				continue
+			}
+			oldLeft, newLeft = 1, 1
+			if m[1] != "" {
+				oldLeft, _ = strconv.Atoi(m[1])
+			}
+			if m[2] != "" {
+				newLeft, _ = strconv.Atoi(m[2])
+			}
+			inHeader = false
+			diff = append(diff, line)
+		case cur != nil && inHeader:
+			readPatchHeader(cur, line)
+			diff = append(diff, line)
+		}
+	}
+	flush()
+
+	for i := range files {
+		f := &files[i]
+		if f.Path == "" {
+			f.Path = f.OldPath
+		}
+		if f.OldPath == f.Path || (f.Status != "R" && f.Status != "C") {
+			f.OldPath = ""
+		}
+	}
+	return files
+}
+
+// readPatchHeader records what an extended git header line says about the file
+func readPatchHeader(f *PatchFile, line string) {
+	switch {
+	case strings.HasPrefix(line, "new file mode"):
+		f.Status = "A"
+	case strings.HasPrefix(line, "deleted file mode"):
+		f.Status = "D"
+	case strings.HasPrefix(line, "rename from "):
+		f.Status = "R"
+		f.OldPath = strings.TrimPrefix(line, "rename from ")
+	case strings.HasPrefix(line, "rename to "):
+		f.Path = strings.TrimPrefix(line, "rename to ")
+	case strings.HasPrefix(line, "copy from "):
+		f.Status = "C"
+		f.OldPath = strings.TrimPrefix(line, "copy from ")
+	case strings.HasPrefix(line, "copy to "):
+		f.Path = strings.TrimPrefix(line, "copy to ")
+	case strings.HasPrefix(line, "similarity index "):
+		f.Similarity, _ = strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(line, "similarity index "), "%"))
+	}
+}
+
+// patchPath reads the path from a ---/+++ line, without its a/ or b/ prefix or a
+// timestamp; empty for /dev/null
+func patchPath(spec string) string {
+	if i := strings.Index(spec, "\t"); i >= 0 {
+		spec = spec[:i]
+	}
+	if spec == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(spec, "a/") || strings.HasPrefix(spec, "b/") {
+		return spec[2:]
+	}
+	return spec
+}
@@ -0,0 +1,30 @@
+package git
+
+import "sync/atomic"
+
+// pathspec holds the glob that commit and file listings are limited to
+type pathspec struct {
+	glob atomic.Pointer[string]
+}
+
+// SetPathspec limits commit and file listings to paths matching glob (e.g. "*.go"
+// or "internal/**"); an empty glob lifts the limit
+func (s *Service) SetPathspec(glob string) {
+	s.paths.glob.Store(&glob)
+}
+
+// Pathspec returns the glob listings are limited to, empty when there is none
+func (s *Service) Pathspec() string {
+	if glob := s.paths.glob.Load(); glob != nil {
+		return *glob
+	}
+	return ""
+}
+
+// limitPaths appends the active pathspec, if any, to arguments ending in "--"
+func (s *Service) limitPaths(args ...string) []string {
+	if glob := s.Pathspec(); glob != "" {
+		return append(args, glob)
+	}
+	return args
+}
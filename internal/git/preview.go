@@ -0,0 +1,129 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PickPreview is the result of applying a commit onto HEAD in a throwaway index
+type PickPreview struct {
+	Files []FileStatus      // Files the operation would change; conflicts have status "U"
+	Diffs map[string]string // Colored diff against HEAD per file
+}
+
+// PreviewCherryPick shows what cherry-picking commitHash onto HEAD would produce
+func (s *Service) PreviewCherryPick(commitHash string) (*PickPreview, error) {
+	return s.previewApply(commitHash, false)
+}
+
+// PreviewRevert shows what reverting commitHash on top of HEAD would produce
+func (s *Service) PreviewRevert(commitHash string) (*PickPreview, error) {
+	return s.previewApply(commitHash, true)
+}
+
+// previewApply applies the commit's patch to a temporary index seeded from HEAD,
+// so neither the user's index nor working tree is touched
+func (s *Service) previewApply(commitHash string, reverse bool) (*PickPreview, error) {
+	tmp, err := os.CreateTemp("", "var-index-*")
+	if err != nil {
+		return nil, err
+	}
+	indexPath := tmp.Name()
+	tmp.Close()
+	// read-tree refuses an empty file, so let it create the index itself
+	os.Remove(indexPath)
+	defer os.Remove(indexPath)
+
+	env := append(os.Environ(), "GIT_INDEX_FILE="+indexPath)
+	run := func(stdin []byte, args ...string) ([]byte, error) {
+		cmd := s.gitCommand(args...)
+		cmd.Env = env
+		if stdin != nil {
+			cmd.Stdin = bytes.NewReader(stdin)
+		}
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		output, err := s.run(cmd)
+		if err != nil && stderr.Len() > 0 {
+			err = fmt.Errorf("%s", strings.TrimSpace(stderr.String()))
+		}
+		return output, err
+	}
+
+	if _, err := run(nil, "read-tree", "HEAD"); err != nil {
+		return nil, err
+	}
+
+	patch, err := run(nil, "diff-tree", "-p", "--binary", "--root", "--no-commit-id", commitHash)
+	if err != nil {
+		return nil, err
+	}
+	if len(patch) == 0 {
+		return nil, fmt.Errorf("%s has no changes to apply (merge commits are not supported)", commitHash)
+	}
+
+	args := []string{"apply", "--cached", "--3way"}
+	if reverse {
+		args = append(args, "-R")
+	}
+	// A conflicted 3-way apply exits non-zero but still records unmerged entries
+	_, applyErr := run(patch, args...)
+
+	output, err := run(nil, "diff", "--cached", "--name-status", "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	preview := &PickPreview{Diffs: make(map[string]string)}
+	for _, file := range parseNameStatus(string(output)) {
+		// Unmerged paths are listed once per stage
+		if len(preview.Files) > 0 && preview.Files[len(preview.Files)-1].Path == file.Path {
+			continue
+		}
+		preview.Files = append(preview.Files, file)
+	}
+	if len(preview.Files) == 0 {
+		if applyErr != nil {
+			return nil, applyErr
+		}
+		return preview, nil
+	}
+
+	for _, f := range preview.Files {
+		if f.Status == "U" {
+			preview.Diffs[f.Path] = s.conflictDiff(f.Path, run)
+			continue
+		}
+		diff, _ := run(nil, "diff", "--cached", "--color=always", "HEAD", "--", f.Path)
+		preview.Diffs[f.Path] = string(diff)
+	}
+	return preview, nil
+}
+
+// conflictDiff renders a conflicted path as a diff from HEAD's version to the
+// merged content with conflict markers
+func (s *Service) conflictDiff(filePath string, run func([]byte, ...string) ([]byte, error)) string {
+	dir, err := os.MkdirTemp("", "var-conflict-*")
+	if err != nil {
+		return fmt.Sprintf("CONFLICT: %s", filePath)
+	}
+	defer os.RemoveAll(dir)
+
+	// Stages 1/2/3 are base/ours/theirs; a missing stage means the side deleted the file
+	stages := make([]string, 3)
+	for i := range stages {
+		content, _ := run(nil, "show", fmt.Sprintf(":%d:%s", i+1, filePath))
+		stages[i] = fmt.Sprintf("%s/stage%d", dir, i+1)
+		os.WriteFile(stages[i], content, 0o644)
+	}
+	base, ours, theirs := stages[0], stages[1], stages[2]
+
+	// merge-file exits with the number of conflicts, so ignore the error
+	merged, _ := run(nil, "merge-file", "-p", "-L", "HEAD", "-L", "base", "-L", "incoming", ours, base, theirs)
+	mergedPath := dir + "/merged"
+	os.WriteFile(mergedPath, merged, 0o644)
+
+	diff, _ := run(nil, "diff", "--no-index", "--color=always", ours, mergedPath)
+	return fmt.Sprintf("CONFLICT: %s\n%s", filePath, diff)
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package git
+
+import "os/exec"
+
+func setProcessGroup(cmd *exec.Cmd) {}
+
+func detachFromTerminal(cmd *exec.Cmd) {}
+
+func killProcessGroup(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		cmd.Process.Kill()
+	}
+}
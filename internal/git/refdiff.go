@@ -0,0 +1,48 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ResolveRef returns the commit a branch, tag or revision expression (HEAD~3, main^) names
+func (s *Service) ResolveRef(name string) (string, error) {
+	if !s.refExists(name) {
+		return "", fmt.Errorf("unknown ref %s", name)
+	}
+	output, err := s.runGit("rev-parse", "--verify", "--quiet", "--short", name+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown ref %s", name)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// FullHash returns the full hash of the commit rev names
+func (s *Service) FullHash(rev string) (string, error) {
+	if strings.HasPrefix(rev, "-") {
+		return "", fmt.Errorf("unknown commit %s", rev)
+	}
+	output, err := s.runGit("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown commit %s", rev)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetFilesBetweenCommits returns the files that differ between two commits
+func (s *Service) GetFilesBetweenCommits(fromHash, toHash string) ([]FileStatus, error) {
+	output, err := s.runGit(s.limitPaths("diff", "--name-status", "-M", fromHash, toHash, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(string(output)), nil
+}
+
+// GetRenameDiffBetweenCommits returns how a file renamed between two commits changed
+func (s *Service) GetRenameDiffBetweenCommits(oldPath, newPath, fromHash, toHash string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--color=always", "-M", fromHash, toHash, "--", oldPath, newPath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
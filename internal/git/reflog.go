@@ -0,0 +1,72 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetHeadReflog returns HEAD's reflog, newest first, with each entry's selector (HEAD@{3})
+// as its Ref and leading its message
+func (s *Service) GetHeadReflog(limit int) ([]Commit, error) {
+	output, err := s.runGit(s.limitPaths("log", "-g", "--format=%h%x00%gd%x00%gs", "-n", fmt.Sprintf("%d", limit), "HEAD", "--")...)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Commit
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		entries = append(entries, Commit{Hash: parts[0], Message: parts[1] + ": " + parts[2], Ref: parts[1]})
+	}
+	return entries, nil
+}
+
+// reflogStepBase returns what a reflog entry is compared against: the entry before it,
+// or for the oldest entry its commit's parent (the empty tree for a root commit)
+func (s *Service) reflogStepBase(entryRef string) (string, error) {
+	prev, err := PreviousReflogRef(entryRef)
+	if err != nil {
+		return "", err
+	}
+	// Suffixes like ^{commit} can't follow a reflog selector: git reads them as part of a date
+	if _, err := s.runGit("rev-parse", "--verify", "--quiet", prev); err == nil {
+		return prev, nil
+	}
+	hash, err := s.runGit("rev-parse", "--verify", "--quiet", entryRef)
+	if err != nil {
+		return "", fmt.Errorf("unknown reflog entry %s", entryRef)
+	}
+	if parent := strings.TrimSpace(string(hash)) + "^"; s.refExists(parent) {
+		return parent, nil
+	}
+	return emptyTree, nil
+}
+
+// GetReflogStepFiles returns the files a reflog step changed
+func (s *Service) GetReflogStepFiles(entryRef string) ([]FileStatus, error) {
+	base, err := s.reflogStepBase(entryRef)
+	if err != nil {
+		return nil, err
+	}
+	output, err := s.runGit(s.limitPaths("diff", "--name-status", base, entryRef, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNameStatus(string(output)), nil
+}
+
+// GetReflogStepDiff returns what a reflog step changed in a file
+func (s *Service) GetReflogStepDiff(filePath, entryRef string) (string, error) {
+	base, err := s.reflogStepBase(entryRef)
+	if err != nil {
+		return "", err
+	}
+	output, err := s.runGit(s.convertText("diff", "--color=always", base, entryRef, "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
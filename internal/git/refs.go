@@ -0,0 +1,88 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Ref is a branch or tag and the commit it points at
+type Ref struct {
+	Name string // Short name (main, origin/main, v1.2.0)
+	Kind string // "branch", "remote" or "tag"
+	Hash string // Abbreviated hash of the commit, annotated tags peeled
+
+	// Number of parents of the commit, more than one for a merge
+	Parents int
+
+	// Commits a local branch has that its upstream lacks, and the reverse; both zero
+	// without an upstream
+	Ahead, Behind int
+}
+
+// Divergence returns a branch's commits ahead of and behind its upstream as "↑2 ↓1",
+// or an empty string when they match or it has no upstream
+func (r Ref) Divergence() string {
+	if r.Ahead == 0 && r.Behind == 0 {
+		return ""
+	}
+	return fmt.Sprintf("↑%d ↓%d", r.Ahead, r.Behind)
+}
+
+// refKinds orders the kinds of ref, and maps ref namespaces to them
+var refKinds = []struct{ prefix, kind string }{
+	{"refs/heads/", "branch"},
+	{"refs/remotes/", "remote"},
+	{"refs/tags/", "tag"},
+}
+
+// GetRefs returns the local branches, remote branches and tags, most recent first within each
+func (s *Service) GetRefs() ([]Ref, error) {
+	output, err := s.runGit("for-each-ref", "--sort=-creatordate",
+		"--format=%(refname)%00%(objectname:short)%00%(*objectname:short)%00%(symref)%00%(upstream:track,nobracket)%00%(parent)%00%(*parent)",
+		"refs/heads", "refs/remotes", "refs/tags")
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []Ref
+	order := make(map[string]int)
+	for _, line := range strings.Split(string(output), "\n") {
+		fields := strings.Split(line, "\x00")
+		if len(fields) < 7 || fields[3] != "" {
+			// Skip symbolic refs such as origin/HEAD
+			continue
+		}
+		hash, parents := fields[1], fields[5]
+		if fields[2] != "" {
+			hash, parents = fields[2], fields[6]
+		}
+		for i, k := range refKinds {
+			if name, ok := strings.CutPrefix(fields[0], k.prefix); ok {
+				ref := Ref{Name: name, Kind: k.kind, Hash: hash, Parents: len(strings.Fields(parents))}
+				ref.Ahead, ref.Behind = parseTrack(fields[4])
+				refs = append(refs, ref)
+				order[k.kind] = i
+				break
+			}
+		}
+	}
+	sort.SliceStable(refs, func(i, j int) bool {
+		return order[refs[i].Kind] < order[refs[j].Kind]
+	})
+	return refs, nil
+}
+
+// parseTrack reads %(upstream:track,nobracket): "ahead 2, behind 1", either half alone,
+// "gone" for a deleted upstream, or nothing when the branch matches its upstream
+func parseTrack(track string) (int, int) {
+	var ahead, behind int
+	for _, part := range strings.Split(track, ", ") {
+		if n, ok := strings.CutPrefix(part, "ahead "); ok {
+			fmt.Sscanf(n, "%d", &ahead)
+		} else if n, ok := strings.CutPrefix(part, "behind "); ok {
+			fmt.Sscanf(n, "%d", &behind)
+		}
+	}
+	return ahead, behind
+}
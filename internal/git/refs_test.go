@@ -0,0 +1,22 @@
+package git
+
+import "testing"
+
+func TestParseTrack(t *testing.T) {
+	tests := []struct {
+		track         string
+		ahead, behind int
+	}{
+		{"", 0, 0},
+		{"ahead 2", 2, 0},
+		{"behind 13", 0, 13},
+		{"ahead 2, behind 1", 2, 1},
+		{"gone", 0, 0},
+	}
+	for _, tt := range tests {
+		ahead, behind := parseTrack(tt.track)
+		if ahead != tt.ahead || behind != tt.behind {
+			t.Errorf("parseTrack(%q) = %d, %d; want %d, %d", tt.track, ahead, behind, tt.ahead, tt.behind)
+		}
+	}
+}
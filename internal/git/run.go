@@ -0,0 +1,125 @@
+package git
+
+import (
+	"bytes"
+	"errors"
+	"os/exec"
+	"slices"
+	"sync"
+	"time"
+)
+
+// errClosed is returned for commands started after Close
+var errClosed = errors.New("git service is shutting down")
+
+// processes tracks running git children so they can be terminated on shutdown
+type processes struct {
+	mu      sync.Mutex
+	running map[*exec.Cmd]struct{}
+	closed  bool
+}
+
+// defaultArgs come before the configured global options, which can override them.
+// core.quotepath=false keeps non-ASCII paths as UTF-8 instead of octal escapes.
+var defaultArgs = []string{"-c", "core.quotepath=false"}
+
+// gitCommand builds a git command that runs in the repository, with the configured global options
+func (s *Service) gitCommand(args ...string) *exec.Cmd {
+	cmd := exec.Command(s.gitPath, slices.Concat(defaultArgs, s.globalArgs, args)...)
+	cmd.Dir = s.repoPath
+	return cmd
+}
+
+// runGit runs git in the repository and returns its stdout
+func (s *Service) runGit(args ...string) ([]byte, error) {
+	return s.run(s.gitCommand(args...))
+}
+
+// run executes cmd in its own process group, tracked until it exits so that
+// Close can terminate it along with anything it spawned (pagers, hooks, textconv)
+func (s *Service) run(cmd *exec.Cmd) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	if cmd.Stderr == nil {
+		// Capture stderr so *exec.ExitError carries it, as with cmd.Output()
+		cmd.Stderr = &bytes.Buffer{}
+	}
+	setProcessGroup(cmd)
+
+	start := time.Now()
+	s.procs.mu.Lock()
+	if s.procs.closed {
+		s.procs.mu.Unlock()
+		return nil, errClosed
+	}
+	if err := cmd.Start(); err != nil {
+		s.procs.mu.Unlock()
+		s.recordCommand(cmd, start, err)
+		return nil, err
+	}
+	s.procs.running[cmd] = struct{}{}
+	s.procs.mu.Unlock()
+	if s.scope != nil {
+		s.scope.mu.Lock()
+		s.scope.running[cmd] = struct{}{}
+		s.scope.mu.Unlock()
+	}
+
+	err := cmd.Wait()
+
+	s.procs.mu.Lock()
+	delete(s.procs.running, cmd)
+	s.procs.mu.Unlock()
+	if s.scope != nil {
+		s.scope.mu.Lock()
+		delete(s.scope.running, cmd)
+		s.scope.mu.Unlock()
+	}
+
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if buf, ok := cmd.Stderr.(*bytes.Buffer); ok {
+			exitErr.Stderr = buf.Bytes()
+		}
+	}
+	s.recordCommand(cmd, start, err)
+	return stdout.Bytes(), err
+}
+
+// Scoped returns a service for one piece of work, such as a slow load the user may
+// cancel. It shares s's settings and command log, and Close on s still stops its
+// commands, but its own Cancel stops only the commands started through it.
+func (s *Service) Scoped() *Service {
+	scoped := *s
+	scoped.scope = &processes{running: make(map[*exec.Cmd]struct{})}
+	return &scoped
+}
+
+// Unlogged returns a service whose commands stay out of the command log, for
+// background checks frequent enough to crowd out the commands worth seeing
+func (s *Service) Unlogged() *Service {
+	unlogged := *s
+	unlogged.unlogged = true
+	return &unlogged
+}
+
+// Cancel terminates the git processes currently running, or for a scoped service
+// those it started; later commands run as usual
+func (s *Service) Cancel() {
+	procs := s.procs
+	if s.scope != nil {
+		procs = s.scope
+	}
+	procs.mu.Lock()
+	defer procs.mu.Unlock()
+	for cmd := range procs.running {
+		killProcessGroup(cmd)
+	}
+}
+
+// Close terminates any git processes still running and rejects new ones
+func (s *Service) Close() {
+	s.procs.mu.Lock()
+	s.procs.closed = true
+	s.procs.mu.Unlock()
+	s.Cancel()
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// newTestRepo creates an empty repository in a temporary directory, isolated from the
+// user's git config
+func newTestRepo(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+	gitIn(t, dir, "init", "-q")
+	return dir
+}
+
+// gitIn runs a git command in dir, failing the test if it fails
+func gitIn(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+func TestNonASCIIPathsUnquoted(t *testing.T) {
+	dir := newTestRepo(t)
+	// Quoting is git's default; the service must turn it off whatever the config says
+	gitIn(t, dir, "config", "core.quotepath", "true")
+	gitIn(t, dir, "commit", "-q", "--allow-empty", "-m", "initial")
+
+	committed := []string{"café.txt", "日本語.md", filepath.Join("naïve dir", "ünïcode.go")}
+	for _, path := range committed {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte("content\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitIn(t, dir, "add", ".")
+	gitIn(t, dir, "commit", "-q", "-m", "add files")
+	if err := os.WriteFile(filepath.Join(dir, "中文.txt"), []byte("new\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewService(dir, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := s.GetFilesInCommit("HEAD")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, file := range files {
+		paths = append(paths, file.Path)
+	}
+	slices.Sort(paths)
+	want := slices.Sorted(slices.Values(committed))
+	if !slices.Equal(paths, want) {
+		t.Errorf("GetFilesInCommit paths = %q, want %q", paths, want)
+	}
+
+	modified, err := s.GetModifiedFiles()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modified) != 1 || modified[0].Path != "中文.txt" {
+		t.Errorf("GetModifiedFiles = %+v, want the untracked 中文.txt", modified)
+	}
+}
@@ -1,36 +1,137 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Service struct {
-	repoPath string
+	repoPath   string
+	gitPath    string   // git executable
+	globalArgs []string // Options placed before every subcommand (e.g. -c core.quotepath=false)
+	procs      *processes
+	paths      *pathspec
+	conv       *textconv
+	hunks      *hunkJoin
+	commands   *commandLog // Latest commands run, for the command log
+
+	// Set on the services Scoped and Unlogged return: the commands started through
+	// this one, for Cancel to stop, and whether they stay out of the command log
+	scope    *processes
+	unlogged bool
 }
 
 type FileStatus struct {
-	Path   string
-	Status string // M, A, D, ??, etc.
+	Path       string
+	Status     string // M, A, D, R, C, ??, etc.
+	OldPath    string // Original path of a rename or copy
+	Similarity int    // Similarity percentage of a rename or copy
 }
 
 type Commit struct {
 	Hash    string
 	Message string
+	Ref     string    // Reflog selector (e.g. HEAD@{3}, stash@{0}) for reflog and stash entries
+	Date    time.Time // Author date, zero where the list doesn't read it
+	Author  string    // Author name, empty where the list doesn't read it
+	Refs    []string  // Branches and tags pointing at the commit, as decorations list them
+	Parents int       // Number of parents, more than one for a merge; zero where the list doesn't read them
+}
+
+// commitLogFormat prints an abbreviated hash, author timestamp, author name, parent
+// hashes, ref decorations and subject per commit, split by NULs so that commits with an
+// empty subject still parse
+const commitLogFormat = "--format=%h%x00%at%x00%an%x00%p%x00%D%x00%s"
+
+// parseCommitLog reads commitLogFormat output, naming commits without a subject "(no message)"
+func parseCommitLog(output string) []Commit {
+	var commits []Commit
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 6)
+		if len(fields) < 6 {
+			continue
+		}
+		subject := strings.TrimSpace(fields[5])
+		if subject == "" {
+			subject = "(no message)"
+		}
+		commit := Commit{
+			Hash:    fields[0],
+			Message: subject,
+			Author:  fields[2],
+			Parents: len(strings.Fields(fields[3])),
+			Refs:    parseDecorations(fields[4]),
+		}
+		if ts, err := strconv.ParseInt(fields[1], 10, 64); err == nil {
+			commit.Date = time.Unix(ts, 0)
+		}
+		commits = append(commits, commit)
+	}
+	return commits
+}
+
+// parseDecorations reads %D ("HEAD -> main, origin/main, tag: v1.0") into ref names,
+// leaving out HEAD itself
+func parseDecorations(decorations string) []string {
+	var refs []string
+	for _, d := range strings.Split(decorations, ", ") {
+		d = strings.TrimPrefix(d, "HEAD -> ")
+		d = strings.TrimPrefix(d, "tag: ")
+		if d != "" && d != "HEAD" {
+			refs = append(refs, d)
+		}
+	}
+	return refs
 }
 
-func NewService(repoPath string) *Service {
-	return &Service{repoPath: repoPath}
+// NewService creates a service for the repository at repoPath. gitPath is the git
+// executable, empty for git on PATH; globalArgs go before every git subcommand.
+func NewService(repoPath, gitPath string, globalArgs []string) (*Service, error) {
+	configured := gitPath != ""
+	if !configured {
+		gitPath = "git"
+	}
+	resolved, err := exec.LookPath(gitPath)
+	if err != nil {
+		if !configured && errors.Is(err, exec.ErrNotFound) {
+			return nil, errors.New("git is not installed or not on PATH")
+		}
+		return nil, fmt.Errorf("git executable not found: %w", err)
+	}
+	return &Service{
+		repoPath:   repoPath,
+		gitPath:    resolved,
+		globalArgs: globalArgs,
+		procs:      &processes{running: make(map[*exec.Cmd]struct{})},
+		paths:      &pathspec{},
+		conv:       &textconv{},
+		hunks:      &hunkJoin{},
+		commands:   &commandLog{},
+	}, nil
+}
+
+// RepoPath returns the absolute path of the repository
+func (s *Service) RepoPath() string {
+	return s.repoPath
+}
+
+// GetHead returns the full hash HEAD points to
+func (s *Service) GetHead() (string, error) {
+	output, err := s.runGit("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // GetModifiedFiles returns a list of modified, added, or untracked files
 func (s *Service) GetModifiedFiles() ([]FileStatus, error) {
-	cmd := exec.Command("git", "status", "--porcelain")
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("status", "--porcelain")
 	if err != nil {
 		return nil, err
 	}
@@ -63,9 +164,7 @@ func (s *Service) GetDiff(filePath string) (string, error) {
 
 // GetDiffWithContext returns the diff with specified lines of context
 func (s *Service) GetDiffWithContext(filePath string, context int) (string, error) {
-	cmd := exec.Command("git", "diff", "--color=always", fmt.Sprintf("-U%d", context), "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit(s.joinHunks(context, s.convertText("diff", "--color=always", fmt.Sprintf("-U%d", context), "--", filePath))...)
 	if err != nil {
 		// If file is untracked, show the whole file as added
 		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 0 {
@@ -80,8 +179,7 @@ func (s *Service) GetDiffWithContext(filePath string, context int) (string, erro
 // GetFileContent returns the full content of a file in the working copy with line numbers
 func (s *Service) GetFileContent(filePath string) (string, error) {
 	fullPath := filepath.Join(s.repoPath, filePath)
-	cmd := exec.Command("cat", "-n", fullPath)
-	output, err := cmd.Output()
+	output, err := s.run(exec.Command("cat", "-n", fullPath))
 	if err != nil {
 		return "", err
 	}
@@ -91,38 +189,48 @@ func (s *Service) GetFileContent(filePath string) (string, error) {
 // getUntrackedDiff returns a diff-like output for untracked files
 func (s *Service) getUntrackedDiff(filePath string) (string, error) {
 	fullPath := filepath.Join(s.repoPath, filePath)
-	cmd := exec.Command("git", "diff", "--color=always", "--no-index", "/dev/null", fullPath)
-	cmd.Dir = s.repoPath
-	output, _ := cmd.Output() // This will return exit code 1 for differences
+	output, _ := s.runGit(s.convertText("diff", "--color=always", "--no-index", "/dev/null", fullPath)...) // This will return exit code 1 for differences
 	return string(output), nil
 }
 
 // GetFileCommits returns the commit history for a specific file
 func (s *Service) GetFileCommits(filePath string) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "--oneline", "--follow", "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("log", commitLogFormat, "--follow", "--", filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	var commits []Commit
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) < 2 {
-			continue
-		}
-		commits = append(commits, Commit{
-			Hash:    parts[0],
-			Message: parts[1],
-		})
+	return parseCommitLog(string(output)), nil
+}
+
+// GetFileCommitCount returns how many commits reachable from HEAD touched the file
+func (s *Service) GetFileCommitCount(filePath string) (int, error) {
+	output, err := s.runGit("rev-list", "--count", "HEAD", "--", filePath)
+	if err != nil {
+		return 0, err
 	}
-	return commits, nil
+	return strconv.Atoi(strings.TrimSpace(string(output)))
+}
+
+// LastChange is the commit that last touched a file
+type LastChange struct {
+	Hash   string
+	Author string
+	When   string // Relative, e.g. "2 days ago"
+}
+
+// GetLastChange returns the commit reachable from HEAD that last touched the file,
+// or nil if none has
+func (s *Service) GetLastChange(filePath string) (*LastChange, error) {
+	output, err := s.runGit("log", "-1", "--format=%h%x00%an%x00%ar", "HEAD", "--", filePath)
+	if err != nil {
+		return nil, err
+	}
+	parts := strings.Split(strings.TrimSpace(string(output)), "\x00")
+	if len(parts) < 3 {
+		return nil, nil
+	}
+	return &LastChange{Hash: parts[0], Author: parts[1], When: parts[2]}, nil
 }
 
 // GetDiffAtCommit returns the diff for a file at a specific commit
@@ -130,11 +238,18 @@ func (s *Service) GetDiffAtCommit(filePath, commitHash string) (string, error) {
 	return s.GetDiffAtCommitWithContext(filePath, commitHash, 3)
 }
 
+// GetRenameDiffAtCommit returns the diff of a renamed or copied file, pairing its old and new paths
+func (s *Service) GetRenameDiffAtCommit(oldPath, newPath, commitHash string) (string, error) {
+	output, err := s.runGit(s.convertText("show", "--color=always", "--decorate=short", "--no-notes", "-M", "-C", commitHash, "--", oldPath, newPath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
 // GetDiffAtCommitWithContext returns the diff with specified lines of context
 func (s *Service) GetDiffAtCommitWithContext(filePath, commitHash string, context int) (string, error) {
-	cmd := exec.Command("git", "show", "--color=always", fmt.Sprintf("-U%d", context), commitHash, "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit(s.joinHunks(context, s.convertText("show", "--color=always", "--decorate=short", "--no-notes", fmt.Sprintf("-U%d", context), commitHash, "--", filePath))...)
 	if err != nil {
 		return "", err
 	}
@@ -143,18 +258,17 @@ func (s *Service) GetDiffAtCommitWithContext(filePath, commitHash string, contex
 
 // GetFileContentAtCommit returns the full content of a file at a specific commit
 func (s *Service) GetFileContentAtCommit(filePath, commitHash string) (string, error) {
-	cmd := exec.Command("git", "show", fmt.Sprintf("%s:%s", commitHash, filePath))
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit(s.convertText("show", fmt.Sprintf("%s:%s", commitHash, filePath))...)
 	if err != nil {
 		// File might be deleted in this commit, try parent commit
-		cmd = exec.Command("git", "show", fmt.Sprintf("%s^:%s", commitHash, filePath))
-		cmd.Dir = s.repoPath
-		output, err = cmd.Output()
+		output, err = s.runGit(s.convertText("show", fmt.Sprintf("%s^:%s", commitHash, filePath))...)
 		if err != nil {
 			return "", err
 		}
 	}
+	if ptr, ok := parseLFSPointer(string(output)); ok {
+		return s.formatLFSPointer(ptr), nil
+	}
 	// Add line numbers manually
 	lines := strings.Split(string(output), "\n")
 	var result strings.Builder
@@ -167,60 +281,112 @@ func (s *Service) GetFileContentAtCommit(filePath, commitHash string) (string, e
 	return result.String(), nil
 }
 
+// GetRawFileAtCommit returns a file's content at a commit exactly as stored, without line
+// numbers or a textconv conversion; like GetFileContentAtCommit, a file the commit deletes
+// is read from its parent
+func (s *Service) GetRawFileAtCommit(filePath, commitHash string) (string, error) {
+	output, err := s.runGit("show", fmt.Sprintf("%s:%s", commitHash, filePath))
+	if err != nil {
+		output, err = s.runGit("show", fmt.Sprintf("%s^:%s", commitHash, filePath))
+		if err != nil {
+			return "", err
+		}
+	}
+	return string(output), nil
+}
+
 // GetRecentCommits returns recent commits for the repository
 func (s *Service) GetRecentCommits(limit int) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "--oneline", "-n", fmt.Sprintf("%d", limit))
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	return s.GetCommitsFrom("HEAD", limit)
+}
+
+// AllRefs passed as a rev lists the commits of every branch, tag and remote
+const AllRefs = "--all"
+
+// GetCommitsFrom returns up to limit commits reachable from rev, newest first
+func (s *Service) GetCommitsFrom(rev string, limit int) ([]Commit, error) {
+	return s.GetCommitsPage(rev, 0, limit)
+}
+
+// GetCommitsPage returns up to limit commits reachable from rev after skipping the first skip
+func (s *Service) GetCommitsPage(rev string, skip, limit int) ([]Commit, error) {
+	revs := []string{rev}
+	if rev == AllRefs {
+		// The stash's commits aren't history anyone made on a branch
+		revs = []string{"--exclude=refs/stash", AllRefs}
+	}
+	args := []string{"log", commitLogFormat, "-n", fmt.Sprintf("%d", limit), "--skip", fmt.Sprintf("%d", skip)}
+	args = append(append(args, revs...), "--")
+	output, err := s.runGit(s.limitPaths(args...)...)
 	if err != nil {
 		return nil, err
 	}
 
-	var commits []Commit
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) < 2 {
-			continue
-		}
-		commits = append(commits, Commit{
-			Hash:    parts[0],
-			Message: parts[1],
-		})
-	}
-	return commits, nil
+	return parseCommitLog(string(output)), nil
 }
 
 // GetFilesInCommit returns files changed in a specific commit
 func (s *Service) GetFilesInCommit(commitHash string) ([]FileStatus, error) {
-	cmd := exec.Command("git", "diff-tree", "--no-commit-id", "--name-status", "-r", commitHash)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit(s.limitPaths("diff-tree", "--no-commit-id", "--name-status", "-r", "-M", "-C", commitHash, "--")...)
 	if err != nil {
 		return nil, err
 	}
+	return parseNameStatus(string(output)), nil
+}
 
+// parseNameStatus parses --name-status output. Renames and copies list a status
+// with a similarity score and both paths ("R100\told\tnew").
+func parseNameStatus(output string) []FileStatus {
 	var files []FileStatus
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.Fields(line)
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\t")
 		if len(parts) < 2 {
 			continue
 		}
-		files = append(files, FileStatus{
+		file := FileStatus{
 			Status: parts[0],
-			Path:   parts[1],
-		})
+			Path:   parts[len(parts)-1],
+		}
+		if code := parts[0]; len(code) > 1 && (code[0] == 'R' || code[0] == 'C') {
+			file.Status = code[:1]
+			file.Similarity, _ = strconv.Atoi(code[1:])
+		}
+		if len(parts) == 3 {
+			file.OldPath = parts[1]
+		}
+		files = append(files, file)
 	}
-	return files, nil
+	return files
+}
+
+// GetFileStatusAtCommit returns the name-status code (M, A, D, ...) of a file in a commit,
+// or an empty string if the commit did not touch it
+func (s *Service) GetFileStatusAtCommit(filePath, commitHash string) (string, error) {
+	output, err := s.runGit("diff-tree", "--no-commit-id", "--name-status", "-r", "--root", commitHash, "--", filePath)
+	if err != nil {
+		return "", err
+	}
+	files := parseNameStatus(string(output))
+	if len(files) == 0 {
+		return "", nil
+	}
+	return files[0].Status, nil
+}
+
+// GetSubmoduleLog returns the submodule commits between two gitlink values, newest first.
+// It fails if the submodule is not checked out.
+func (s *Service) GetSubmoduleLog(subPath, oldSha, newSha string) ([]Commit, error) {
+	output, err := s.runGit("-C", subPath, "log", commitLogFormat, oldSha+".."+newSha, "--")
+	if err != nil {
+		return nil, err
+	}
+	return parseCommitLog(string(output)), nil
+}
+
+// FileExistsAtCommit reports whether the file is present in the commit's tree
+func (s *Service) FileExistsAtCommit(filePath, commitHash string) bool {
+	_, err := s.runGit("cat-file", "-e", commitHash+":"+filePath)
+	return err == nil
 }
 
 // FileStats holds additions and deletions for a file in a commit
@@ -231,37 +397,40 @@ type FileStats struct {
 
 // GetNumstatForCommit returns per-file addition/deletion counts for a commit
 func (s *Service) GetNumstatForCommit(commitHash string) (map[string]FileStats, error) {
-	cmd := exec.Command("git", "diff-tree", "--numstat", "--no-commit-id", "-r", commitHash)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	// -z keeps paths unquoted and lists renames and copies as separate old/new fields
+	output, err := s.runGit(s.limitPaths("diff-tree", "--numstat", "-z", "--no-commit-id", "-r", "-M", "-C", commitHash, "--")...)
 	if err != nil {
 		return nil, err
 	}
+	return parseNumstat(string(output)), nil
+}
 
+// parseNumstat reads --numstat -z output into stats keyed by the file's new path
+func parseNumstat(output string) map[string]FileStats {
 	stats := make(map[string]FileStats)
-	for _, line := range strings.Split(string(output), "\n") {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.Fields(line)
+	fields := strings.Split(output, "\x00")
+	for i := 0; i < len(fields); i++ {
+		parts := strings.SplitN(fields[i], "\t", 3)
 		if len(parts) < 3 {
 			continue
 		}
+		path := parts[2]
+		if path == "" && i+2 < len(fields) {
+			// Rename or copy: the old and new paths follow as their own fields
+			path = fields[i+2]
+			i += 2
+		}
 		// Binary files show "-" for additions/deletions
 		adds, _ := strconv.Atoi(parts[0])
 		dels, _ := strconv.Atoi(parts[1])
-		path := parts[2]
 		stats[path] = FileStats{Additions: adds, Deletions: dels}
 	}
-	return stats, nil
+	return stats
 }
 
 // GetFileReflog returns reflog entries where the given file was changed
 func (s *Service) GetFileReflog(filePath string, limit int) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "-g", "--oneline", "-n", fmt.Sprintf("%d", limit), "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("log", "-g", "--oneline", "-n", fmt.Sprintf("%d", limit), "--", filePath)
 	if err != nil {
 		return nil, err
 	}
@@ -277,58 +446,95 @@ func (s *Service) GetFileReflog(filePath string, limit int) ([]Commit, error) {
 		if len(parts) < 2 {
 			continue
 		}
-		commits = append(commits, Commit{
+		commit := Commit{
 			Hash:    parts[0],
 			Message: parts[1],
-		})
+		}
+		// Messages start with the entry's selector: "HEAD@{3}: commit: ..."
+		if ref, _, ok := strings.Cut(parts[1], ": "); ok && strings.HasSuffix(ref, "}") {
+			commit.Ref = ref
+		}
+		commits = append(commits, commit)
 	}
 	return commits, nil
 }
 
+// GetReflogEntryDiff returns what a reflog step changed in a file: the diff from
+// the entry's predecessor (ref@{n+1}) to the entry (ref@{n})
+func (s *Service) GetReflogEntryDiff(filePath, entryRef string) (string, error) {
+	return s.GetReflogEntryDiffWithContext(filePath, entryRef, 3)
+}
+
+// GetReflogEntryDiffWithContext returns the reflog step diff with specified lines of context
+func (s *Service) GetReflogEntryDiffWithContext(filePath, entryRef string, context int) (string, error) {
+	prev, err := PreviousReflogRef(entryRef)
+	if err != nil {
+		return "", err
+	}
+	output, err := s.runGit(s.joinHunks(context, s.convertText("diff", "--color=always", fmt.Sprintf("-U%d", context), prev, entryRef, "--", filePath))...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// PreviousReflogRef returns the reflog entry before entryRef (HEAD@{3} -> HEAD@{4})
+func PreviousReflogRef(entryRef string) (string, error) {
+	name, index, ok := strings.Cut(strings.TrimSuffix(entryRef, "}"), "@{")
+	n, err := strconv.Atoi(index)
+	if !ok || err != nil {
+		return "", fmt.Errorf("not a reflog entry: %s", entryRef)
+	}
+	return fmt.Sprintf("%s@{%d}", name, n+1), nil
+}
+
 // GetBlame returns blame output for a file at a specific commit
 func (s *Service) GetBlame(filePath, commitHash string) (string, error) {
-	cmd := exec.Command("git", "--no-pager", "blame", commitHash, "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("--no-pager", "blame", commitHash, "--", filePath)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
+
+// GetBlameRange blames only lines startLine to endLine (1-based, inclusive) of a file
+func (s *Service) GetBlameRange(filePath, commitHash string, startLine, endLine int) (string, error) {
+	rangeArg := fmt.Sprintf("%d,%d", startLine, endLine)
+	output, err := s.runGit("--no-pager", "blame", "-L", rangeArg, commitHash, "--", filePath)
 	if err != nil {
 		return "", err
 	}
 	return string(output), nil
 }
 
+// GetLineOrigin returns the full hash of the commit that last changed a line
+// (1-based) of the file as it is at commitHash
+func (s *Service) GetLineOrigin(filePath, commitHash string, line int) (string, error) {
+	rangeArg := fmt.Sprintf("%d,%d", line, line)
+	output, err := s.runGit("blame", "--porcelain", "-L", rangeArg, commitHash, "--", filePath)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(output))
+	if len(fields) == 0 {
+		return "", fmt.Errorf("no blame for line %d", line)
+	}
+	return fields[0], nil
+}
+
 // GetPickaxeCommits returns commits where the given search term was added or removed
 func (s *Service) GetPickaxeCommits(filePath, searchTerm string) ([]Commit, error) {
-	cmd := exec.Command("git", "log", "--oneline", "-S", searchTerm, "--", filePath)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("log", commitLogFormat, "-S", searchTerm, "--", filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	var commits []Commit
-	lines := strings.Split(string(output), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-		parts := strings.SplitN(line, " ", 2)
-		if len(parts) < 2 {
-			continue
-		}
-		commits = append(commits, Commit{
-			Hash:    parts[0],
-			Message: parts[1],
-		})
-	}
-	return commits, nil
+	return parseCommitLog(string(output)), nil
 }
 
 // GetTreeFiles returns all files in the repository at a given commit
 func (s *Service) GetTreeFiles(commitHash string) ([]string, error) {
-	cmd := exec.Command("git", "ls-tree", "-r", "--name-only", commitHash)
-	cmd.Dir = s.repoPath
-	output, err := cmd.Output()
+	output, err := s.runGit("ls-tree", "-r", "--name-only", commitHash)
 	if err != nil {
 		return nil, err
 	}
@@ -343,10 +549,68 @@ func (s *Service) GetTreeFiles(commitHash string) ([]string, error) {
 	return files, nil
 }
 
-// IsGitRepository checks if the path is a git repository
-func IsGitRepository(path string) bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = path
-	err := cmd.Run()
+// IsRepository checks if the service's path is a git repository
+func (s *Service) IsRepository() bool {
+	_, err := s.runGit("rev-parse", "--git-dir")
 	return err == nil
 }
+
+// StashBase selects what a stash is compared against
+type StashBase int
+
+const (
+	StashBaseParent      StashBase = iota // Changes relative to the commit the stash was made on
+	StashBaseWorkingTree                  // Changes applying the stash would make to the working tree
+)
+
+// GetStashes returns stash entries, newest first, with the stash selector in the message
+func (s *Service) GetStashes() ([]Commit, error) {
+	output, err := s.runGit("stash", "list", "--format=%h%x00%gd: %gs")
+	if err != nil {
+		return nil, err
+	}
+
+	var stashes []Commit
+	for _, line := range strings.Split(string(output), "\n") {
+		parts := strings.SplitN(line, "\x00", 2)
+		if len(parts) < 2 {
+			continue
+		}
+		stashes = append(stashes, Commit{
+			Hash:    parts[0],
+			Message: parts[1],
+		})
+	}
+	return stashes, nil
+}
+
+// stashDiffArgs returns the diff range for a stash against the given base
+func stashDiffArgs(stashHash string, base StashBase) []string {
+	if base == StashBaseWorkingTree {
+		// Reverse so the working tree is the old side and the stash the new one
+		return []string{"-R", stashHash}
+	}
+	return []string{stashHash + "^1", stashHash}
+}
+
+// GetStashFiles returns the files a stash changes relative to the given base
+func (s *Service) GetStashFiles(stashHash string, base StashBase) ([]FileStatus, error) {
+	args := append([]string{"diff", "--name-status"}, stashDiffArgs(stashHash, base)...)
+	output, err := s.runGit(s.limitPaths(append(args, "--")...)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseNameStatus(string(output)), nil
+}
+
+// GetStashDiff returns the diff of a file in a stash relative to the given base
+func (s *Service) GetStashDiff(stashHash, filePath string, base StashBase) (string, error) {
+	args := append([]string{"diff", "--color=always"}, stashDiffArgs(stashHash, base)...)
+	args = append(args, "--", filePath)
+	output, err := s.runGit(s.convertText(args...)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
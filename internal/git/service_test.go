@@ -0,0 +1,72 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseNameStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []FileStatus
+	}{
+		{"modified", "M\tmain.go\n", []FileStatus{{Path: "main.go", Status: "M"}}},
+		{"added", "A\tdocs/new.md\n", []FileStatus{{Path: "docs/new.md", Status: "A"}}},
+		{"deleted", "D\told.txt\n", []FileStatus{{Path: "old.txt", Status: "D"}}},
+		{
+			"renamed",
+			"R100\tinternal/old.go\tinternal/new.go\n",
+			[]FileStatus{{Path: "internal/new.go", Status: "R", OldPath: "internal/old.go", Similarity: 100}},
+		},
+		{
+			"copied",
+			"C75\tsrc/a.go\tsrc/b.go\n",
+			[]FileStatus{{Path: "src/b.go", Status: "C", OldPath: "src/a.go", Similarity: 75}},
+		},
+		{
+			"paths with spaces",
+			"R087\tmy notes.txt\tnotes/my notes.txt\n",
+			[]FileStatus{{Path: "notes/my notes.txt", Status: "R", OldPath: "my notes.txt", Similarity: 87}},
+		},
+		{
+			"several files",
+			"M\ta.go\nR100\tb.go\tc.go\nD\td.go\n",
+			[]FileStatus{
+				{Path: "a.go", Status: "M"},
+				{Path: "c.go", Status: "R", OldPath: "b.go", Similarity: 100},
+				{Path: "d.go", Status: "D"},
+			},
+		},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseNameStatus(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseNameStatus(%q) = %+v, want %+v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCommitLog(t *testing.T) {
+	output := "abc1234\x001700000000\x00Ada\x00def5678\x00HEAD -> main, origin/main, tag: v1.0\x00feat: add things\n" +
+		"def5678\x001700000001\x00Bob\x00\x00\x00\n" +
+		"0123abc\x001700000002\x00Cy\x00abc1234 9876fed\x00HEAD\x00Merge branch 'topic', with a comma\n"
+	commits := parseCommitLog(output)
+	want := []Commit{
+		{Hash: "abc1234", Message: "feat: add things", Author: "Ada", Parents: 1, Refs: []string{"main", "origin/main", "v1.0"}},
+		{Hash: "def5678", Message: "(no message)", Author: "Bob"},
+		{Hash: "0123abc", Message: "Merge branch 'topic', with a comma", Author: "Cy", Parents: 2},
+	}
+	if len(commits) != len(want) {
+		t.Fatalf("parseCommitLog returned %d commits, want %d", len(commits), len(want))
+	}
+	for i, c := range commits {
+		c.Date = time.Time{}
+		if !reflect.DeepEqual(c, want[i]) {
+			t.Errorf("commit %d = %+v, want %+v", i, c, want[i])
+		}
+	}
+}
@@ -0,0 +1,27 @@
+package git
+
+import (
+	"strings"
+)
+
+// IsSparseCheckout reports whether the working tree is a sparse checkout
+func (s *Service) IsSparseCheckout() bool {
+	output, err := s.runGit("config", "--bool", "core.sparseCheckout")
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
+// GetSkippedPaths returns the files a sparse checkout leaves out of the working tree:
+// index entries marked skip-worktree
+func (s *Service) GetSkippedPaths() (map[string]bool, error) {
+	output, err := s.runGit("ls-files", "-t", "-z")
+	if err != nil {
+		return nil, err
+	}
+	skipped := make(map[string]bool)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if path, ok := strings.CutPrefix(entry, "S "); ok {
+			skipped[path] = true
+		}
+	}
+	return skipped, nil
+}
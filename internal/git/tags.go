@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GetNearestTag returns the most recent tag reachable from commitHash
+func (s *Service) GetNearestTag(commitHash string) (string, error) {
+	output, err := s.runGit("describe", "--tags", "--abbrev=0", commitHash)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// AnnotatedTag is the tag object of an annotated tag: who made it, when, and its message
+type AnnotatedTag struct {
+	Name    string
+	Tagger  string
+	Date    string
+	Message string
+	Signed  bool
+}
+
+// annotatedTagFormat prints a tag's fields split by NULs, each tag ending with a record
+// separator as messages span lines
+const annotatedTagFormat = "--format=%(objecttype)%00%(refname:short)%00%(taggername) %(taggeremail)%00%(taggerdate:iso)%00%(contents:subject)%00%(contents:body)%00%(contents:signature)%1e"
+
+// GetAnnotatedTags returns the tag objects of the named tags that are annotated, in the
+// order given; lightweight tags have none and are left out
+func (s *Service) GetAnnotatedTags(names []string) ([]AnnotatedTag, error) {
+	args := []string{"for-each-ref", annotatedTagFormat}
+	for _, name := range names {
+		args = append(args, "refs/tags/"+name)
+	}
+	output, err := s.runGit(args...)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]AnnotatedTag)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		fields := strings.Split(strings.TrimPrefix(record, "\n"), "\x00")
+		if len(fields) < 7 || fields[0] != "tag" {
+			continue
+		}
+		message := fields[4]
+		if body := strings.TrimSpace(fields[5]); body != "" {
+			message += "\n\n" + body
+		}
+		found[fields[1]] = AnnotatedTag{
+			Name:    fields[1],
+			Tagger:  fields[2],
+			Date:    fields[3],
+			Message: message,
+			Signed:  fields[6] != "",
+		}
+	}
+
+	// A pattern also matches the tags below it (v1 matches v1/rc), so keep only the named ones
+	var tags []AnnotatedTag
+	for _, name := range names {
+		if tag, ok := found[name]; ok {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// ResolveTag returns the commit a tag points at
+func (s *Service) ResolveTag(name string) (string, error) {
+	output, err := s.runGit("rev-parse", "--verify", "--quiet", "refs/tags/"+name+"^{commit}")
+	if err != nil {
+		return "", fmt.Errorf("unknown tag %s", name)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// GetDiffBetweenCommits returns how a file changed from one commit to another
+func (s *Service) GetDiffBetweenCommits(filePath, fromHash, toHash string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--color=always", fromHash, toHash, "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,31 @@
+package git
+
+import (
+	"slices"
+	"sync/atomic"
+)
+
+// textconv holds whether diffs and file contents go through the repository's
+// textconv filters (.gitattributes diff=driver with diff.<driver>.textconv)
+type textconv struct {
+	off atomic.Bool
+}
+
+// SetTextconv turns textconv filters on or off; they are on by default but can be slow
+func (s *Service) SetTextconv(on bool) {
+	s.conv.off.Store(!on)
+}
+
+// Textconv reports whether textconv filters are applied
+func (s *Service) Textconv() bool {
+	return !s.conv.off.Load()
+}
+
+// convertText adds --textconv or --no-textconv after the subcommand that starts args
+func (s *Service) convertText(args ...string) []string {
+	flag := "--textconv"
+	if !s.Textconv() {
+		flag = "--no-textconv"
+	}
+	return slices.Insert(args, 1, flag)
+}
@@ -0,0 +1,72 @@
+package git
+
+import (
+	"strings"
+)
+
+// emptyTree is the hash of the empty tree, the base of a repository with no commits
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// workingCopyBase is what the working copy is compared against: HEAD, or the empty tree before the first commit
+func (s *Service) workingCopyBase() string {
+	if s.refExists("HEAD") {
+		return "HEAD"
+	}
+	return emptyTree
+}
+
+// IsDirty reports whether the working tree or index has changes, including untracked files
+func (s *Service) IsDirty() bool {
+	output, err := s.runGit("status", "--porcelain", "--untracked-files=normal")
+	return err == nil && len(strings.TrimSpace(string(output))) > 0
+}
+
+// GetWorkingCopyFiles returns every file changed since HEAD, staged or not, followed by untracked files
+func (s *Service) GetWorkingCopyFiles() ([]FileStatus, error) {
+	output, err := s.runGit(s.limitPaths("diff", "--name-status", s.workingCopyBase(), "--")...)
+	if err != nil {
+		return nil, err
+	}
+	files := parseNameStatus(string(output))
+
+	output, err = s.runGit(s.limitPaths("ls-files", "--others", "--exclude-standard", "--")...)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(string(output), "\n") {
+		if path != "" {
+			files = append(files, FileStatus{Path: path, Status: "??"})
+		}
+	}
+	return files, nil
+}
+
+// GetWorkingCopyNumstat returns lines added and deleted per file since HEAD
+func (s *Service) GetWorkingCopyNumstat() (map[string]FileStats, error) {
+	output, err := s.runGit(s.limitPaths("diff", "--numstat", "-z", s.workingCopyBase(), "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return parseNumstat(string(output)), nil
+}
+
+// GetWorkingCopyDiff returns a file's changes since HEAD, staged or not; untracked files show as added
+func (s *Service) GetWorkingCopyDiff(filePath string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--color=always", s.workingCopyBase(), "--", filePath)...)
+	if err != nil {
+		return "", err
+	}
+	if len(output) == 0 {
+		return s.getUntrackedDiff(filePath)
+	}
+	return string(output), nil
+}
+
+// GetWorkingCopyRenameDiff returns the changes since HEAD of a renamed or copied file, pairing its old and new paths
+func (s *Service) GetWorkingCopyRenameDiff(oldPath, newPath string) (string, error) {
+	output, err := s.runGit(s.convertText("diff", "--color=always", "-M", "-C", s.workingCopyBase(), "--", oldPath, newPath)...)
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
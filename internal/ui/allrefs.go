@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commitPageSize is how many commits the list loads at a time; reaching the last one loads more
+const commitPageSize = 100
+
+type commitsPageMsg struct {
+	rev     string
+	commits []git.Commit
+}
+
+// commitLogRev is what the commit list shows the history of
+func (m *Model) commitLogRev() string {
+	switch {
+	case m.allRefs:
+		return git.AllRefs
+	case m.logStart != "":
+		return m.logStart
+	}
+	return "HEAD"
+}
+
+// toggleAllRefs switches the commit list between HEAD's history and that of every branch
+func (m *Model) toggleAllRefs() tea.Cmd {
+	m.pushHistory()
+	m.allRefs = !m.allRefs
+	m.logStart = ""
+	m.repoView = viewCommits
+	m.preview = nil
+	m.commitIndex = 0
+	m.commitList.SetTitle(m.commitListTitle())
+	status := "Showing commits from HEAD"
+	if m.allRefs {
+		status = "Showing commits from all branches"
+	}
+	return tea.Batch(m.setStatus(status), m.loadInitialData)
+}
+
+// loadMoreCommits fetches the next page of the commit list, if there may be one
+func (m *Model) loadMoreCommits() tea.Cmd {
+	if !m.moreCommits || m.loadingMore || m.repoView != viewCommits {
+		return nil
+	}
+	m.loadingMore = true
+	rev := m.commitLogRev()
+	skip := len(m.allCommits)
+	return func() tea.Msg {
+		commits, _ := m.gitService.GetCommitsPage(rev, skip, commitPageSize)
+		return commitsPageMsg{rev: rev, commits: commits}
+	}
+}
+
+// applyCommitsPage appends the next page to the commit list
+func (m *Model) applyCommitsPage(msg commitsPageMsg) {
+	m.loadingMore = false
+	if msg.rev != m.commitLogRev() || m.repoView != viewCommits {
+		// The list was switched to another history meanwhile
+		return
+	}
+	m.moreCommits = len(msg.commits) == commitPageSize
+	m.allCommits = append(m.allCommits, msg.commits...)
+	m.commits = m.filterByType(m.allCommits)
+	if m.singleFileMode {
+		// The list shows the file's history; the new commits appear on exit
+		return
+	}
+	m.populateCommitList(m.commits)
+	m.commitList.SelectIndex(m.commitIndex)
+	m.updateRevisionDisplay()
+}
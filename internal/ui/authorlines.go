@@ -0,0 +1,115 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// authorMark flags the lines by the author being reviewed, in place of the space before
+// every other line
+const authorMark = "▌"
+
+// promptAuthorLines asks whose lines of the file to pick out, and over which range
+func (m *Model) promptAuthorLines() tea.Cmd {
+	if m.displayMode != displayFull {
+		return m.setStatus("Author lines work in full-file view")
+	}
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "author [from..to]"
+	m.textInput.Focus()
+	m.textInputMode = "author"
+	return textinput.Blink
+}
+
+// parseAuthorSpec splits "author [from..to]" into the author, which may have spaces,
+// and the range, empty when there is none
+func parseAuthorSpec(value string) (string, string) {
+	fields := strings.Fields(value)
+	if n := len(fields); n > 1 && strings.Contains(fields[n-1], "..") {
+		return strings.Join(fields[:n-1], " "), fields[n-1]
+	}
+	return strings.Join(fields, " "), ""
+}
+
+// rangeEnd is the commit whose version of a file a range's blame describes
+func rangeEnd(rev string) string {
+	i := strings.LastIndex(rev, "..")
+	if i < 0 {
+		return rev
+	}
+	if end := rev[i+2:]; end != "" {
+		return end
+	}
+	return "HEAD"
+}
+
+// loadAuthorLines shows the file as it is at the end of the range with the lines blame
+// attributes to the author picked out. Without a range, every commit up to the viewed
+// one counts.
+func (m *Model) loadAuthorLines(value string) tea.Cmd {
+	author, rev := parseAuthorSpec(value)
+	if author == "" {
+		return m.setStatus("Enter an author, optionally followed by from..to")
+	}
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	if rev == "" {
+		rev = hash
+	}
+	file := m.currentFile
+	cmd := m.trackOperation("Blaming "+file, func(op *Model) tea.Msg {
+		lines, err := op.gitService.GetAuthorLines(file, rev, author)
+		if err != nil {
+			return diffLoadedMsg{content: fmt.Sprintf("Error: %v", err)}
+		}
+		content, err := op.gitService.GetFileContentAtCommit(file, rangeEnd(rev))
+		if err != nil {
+			return diffLoadedMsg{content: fmt.Sprintf("Error: %v", err)}
+		}
+		count := fmt.Sprintf("%d lines", len(lines))
+		if len(lines) == 1 {
+			count = "1 line"
+		}
+		msg := diffLoadedMsg{
+			content:     content,
+			banner:      fmt.Sprintf("%s by %s in %s", count, author, rev),
+			authorLines: make(map[int]bool, len(lines)),
+		}
+		if len(lines) > 0 {
+			msg.firstChange = lines[0]
+		}
+		for _, line := range lines {
+			msg.authorLines[line] = true
+		}
+		return msg
+	})
+	m.operation.content = true
+	return cmd
+}
+
+// SetAuthorLines picks out the given full-file lines, dimming the rest, until the next
+// content without them; nil shows every line as usual
+func (d *DiffView) SetAuthorLines(lines map[int]bool) {
+	d.authorLines = lines
+}
+
+// markAuthorLines flags the full-file lines in lines and dims the others
+func markAuthorLines(content string, lines map[int]bool) string {
+	rows := strings.Split(content, "\n")
+	for i, row := range rows {
+		n, _, ok := fullFileLine(row)
+		switch {
+		case !ok:
+		case lines[n]:
+			rows[i] = AuthorMarkStyle.Render(authorMark) + row
+		default:
+			rows[i] = " \x1b[2m" + stripANSI(row) + "\x1b[0m"
+		}
+	}
+	return strings.Join(rows, "\n")
+}
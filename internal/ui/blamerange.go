@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptBlameRange asks which lines of the file to blame, starting from the lines on screen
+func (m *Model) promptBlameRange() tea.Cmd {
+	first, last, ok := m.diffView.VisibleFileLines()
+	if !ok {
+		return m.setStatus("Blaming a range works in full-file view")
+	}
+	m.textInput.SetValue(fmt.Sprintf("%d,%d", first, last))
+	m.textInput.CursorEnd()
+	m.textInput.Placeholder = "start,end"
+	m.textInput.Focus()
+	m.textInputMode = "range"
+	return textinput.Blink
+}
+
+// parseLineRange reads "start,end", "start-end" or a single line number
+func parseLineRange(value string) (int, int, bool) {
+	first, last, found := strings.Cut(strings.TrimSpace(value), ",")
+	if !found {
+		first, last, found = strings.Cut(first, "-")
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(first))
+	if err != nil {
+		return 0, 0, false
+	}
+	end := start
+	if found {
+		if end, err = strconv.Atoi(strings.TrimSpace(last)); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, end, start >= 1 && end >= start
+}
+
+// loadBlameRange blames the lines given as "start,end", "start-end" or a single line
+// beside their code
+func (m *Model) loadBlameRange(value string) tea.Cmd {
+	start, end, ok := parseLineRange(value)
+	if !ok {
+		return m.setStatus("Enter lines as start,end or start-end")
+	}
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	file := m.currentFile
+	cmd := m.trackOperation("Blaming "+file, func(op *Model) tea.Msg {
+		rev := hash
+		if status, _ := op.gitService.GetFileStatusAtCommit(file, hash); status == "D" {
+			// The file no longer exists at this commit, so blame its last version
+			rev = hash + "^"
+		}
+		blame, err := op.gitService.GetBlameRange(file, rev, start, end)
+		if err != nil {
+			return sideBySideLoadedMsg{err: err}
+		}
+		label := fmt.Sprintf("%s @ %s, lines %d-%d", file, rev, start, end)
+		return sideBySideLoadedMsg{view: blameColumns(blame, label)}
+	})
+	m.operation.content = true
+	return cmd
+}
@@ -0,0 +1,29 @@
+package ui
+
+import "testing"
+
+func TestParseLineRange(t *testing.T) {
+	tests := []struct {
+		value      string
+		start, end int
+		ok         bool
+	}{
+		{"10,20", 10, 20, true},
+		{"10-20", 10, 20, true},
+		{" 10 - 20 ", 10, 20, true},
+		{"7", 7, 7, true},
+		{"20,10", 0, 0, false},
+		{"0", 0, 0, false},
+		{"10-", 0, 0, false},
+		{"10:20", 0, 0, false},
+		{"10,20x", 0, 0, false},
+		{"", 0, 0, false},
+	}
+	for _, tt := range tests {
+		start, end, ok := parseLineRange(tt.value)
+		if ok != tt.ok || ok && (start != tt.start || end != tt.end) {
+			t.Errorf("parseLineRange(%q) = %d, %d, %v; want %d, %d, %v",
+				tt.value, start, end, ok, tt.start, tt.end, tt.ok)
+		}
+	}
+}
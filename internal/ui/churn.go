@@ -0,0 +1,68 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type commitCountsMsg struct {
+	gen    int
+	counts map[string]int
+}
+
+// toggleCommitCounts shows or hides how many commits have touched each file in the list
+func (m *Model) toggleCommitCounts() tea.Cmd {
+	if m.commitCounts != nil {
+		m.commitCounts = nil
+		m.sidebar.SetCommitCounts(nil)
+		return nil
+	}
+	m.resetCommitCounts()
+	return m.loadCommitCounts()
+}
+
+// resetCommitCounts drops the cached counts, as when HEAD moves and they may be stale
+func (m *Model) resetCommitCounts() {
+	m.commitCounts = map[string]int{}
+	m.commitCountsGen++
+	m.sidebar.SetCommitCounts(m.commitCounts)
+}
+
+// loadCommitCounts counts the commits touching the files on the list's current page
+// that haven't been counted yet, when counts are shown
+func (m *Model) loadCommitCounts() tea.Cmd {
+	if m.commitCounts == nil {
+		return nil
+	}
+	var paths []string
+	for _, p := range m.sidebar.VisiblePaths() {
+		if _, ok := m.commitCounts[p]; !ok {
+			// Mark as pending so scrolling doesn't ask again before the count arrives
+			m.commitCounts[p] = -1
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+
+	gen := m.commitCountsGen
+	return func() tea.Msg {
+		counts := make(map[string]int, len(paths))
+		for _, p := range paths {
+			if n, err := m.gitService.GetFileCommitCount(p); err == nil {
+				counts[p] = n
+			}
+		}
+		return commitCountsMsg{gen: gen, counts: counts}
+	}
+}
+
+// applyCommitCounts caches the counts unless they were computed before the cache was reset
+func (m *Model) applyCommitCounts(msg commitCountsMsg) {
+	if m.commitCounts == nil || msg.gen != m.commitCountsGen {
+		return
+	}
+	for p, n := range msg.counts {
+		m.commitCounts[p] = n
+	}
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// copyToClipboard copies text and reports the outcome in the help bar
+func (m *Model) copyToClipboard(text, what string) tea.Cmd {
+	if err := clipboard.WriteAll(text); err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", err))
+	}
+	return m.setStatus("Copied " + what)
+}
+
+// suggestionBlock formats the added lines of a hunk as a GitHub suggestion block
+func suggestionBlock(hunk []string) (string, bool) {
+	var lines []string
+	for _, line := range hunk {
+		if strings.HasPrefix(line, "+") {
+			lines = append(lines, line[1:])
+		}
+	}
+	if len(lines) == 0 {
+		return "", false
+	}
+
+	// The fence must be longer than any backtick run inside the block
+	fence := "```"
+	for _, line := range lines {
+		for strings.Contains(line, fence) {
+			fence += "`"
+		}
+	}
+	return fence + "suggestion\n" + strings.Join(lines, "\n") + "\n" + fence + "\n", true
+}
+
+// copySuggestion copies the hunk at the top of the diff view as a suggestion block
+func (m *Model) copySuggestion() tea.Cmd {
+	block, ok := suggestionBlock(m.diffView.CurrentHunk())
+	if !ok {
+		return m.setStatus("No added lines in this hunk")
+	}
+	return m.copyToClipboard(block, "suggestion block")
+}
+
+// copyLineReference copies a review comment stub for the line at the top of the diff
+// view: its file:line reference and the line quoted below it
+func (m *Model) copyLineReference() tea.Cmd {
+	if m.currentFile == "" || m.showFileTree {
+		return nil
+	}
+	line, text, ok := m.diffView.TopFileLineText()
+	if !ok {
+		return m.setStatus("No file line at the top of the view")
+	}
+	ref := fmt.Sprintf("%s:L%d", m.currentFile, line)
+	return m.copyToClipboard(ref+"\n> "+text+"\n", ref)
+}
+
+// fileContentMsg carries a file's raw content at a commit, fetched to be copied
+type fileContentMsg struct {
+	label   string // "path@hash", for the status message
+	content string
+	err     error
+}
+
+// copyFileContent copies the whole file being viewed, as stored at the viewed commit
+func (m *Model) copyFileContent() tea.Cmd {
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	file := m.currentFile
+	return func() tea.Msg {
+		content, err := m.gitService.GetRawFileAtCommit(file, hash)
+		return fileContentMsg{label: file + "@" + hash, content: content, err: err}
+	}
+}
+
+func (m *Model) applyFileContent(msg fileContentMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", msg.err))
+	}
+	return m.copyToClipboard(msg.content, msg.label)
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"strings"
+
+	"var/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type codeOwnersLoadedMsg struct {
+	owners *git.CodeOwners
+}
+
+func (m *Model) loadCodeOwners() tea.Msg {
+	owners, _ := m.gitService.LoadCodeOwners()
+	return codeOwnersLoadedMsg{owners: owners}
+}
+
+// toggleOwnerFilter asks for a CODEOWNERS handle to limit the file list and tree to,
+// or lifts the limit if one is set
+func (m *Model) toggleOwnerFilter() tea.Cmd {
+	if m.ownerFilter != "" {
+		return m.setOwnerFilter("")
+	}
+	if m.codeOwners == nil {
+		return m.setStatus("No CODEOWNERS file found")
+	}
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "@org/team or @user"
+	m.textInput.Focus()
+	m.textInputMode = "owner"
+	return textinput.Blink
+}
+
+// setOwnerFilter limits the file list and tree to files owned by owner, or shows all files when empty
+func (m *Model) setOwnerFilter(owner string) tea.Cmd {
+	m.ownerFilter = owner
+	m.restoreFile = m.currentFile
+	if m.showFileTree {
+		return m.loadTreeFiles
+	}
+	return m.loadFilesForCurrentCommit
+}
+
+// ownedFiles annotates files with their owners and drops those the owner filter excludes
+func (m *Model) ownedFiles(files []FileItem) []FileItem {
+	if m.codeOwners == nil {
+		return files
+	}
+	var owned []FileItem
+	for _, f := range files {
+		if m.ownerFilter != "" && !m.codeOwners.IsOwnedBy(f.Path, m.ownerFilter) {
+			continue
+		}
+		f.Owners = strings.Join(m.codeOwners.Owners(f.Path), " ")
+		owned = append(owned, f)
+	}
+	return owned
+}
+
+// ownedPaths drops the paths the owner filter excludes
+func (m *Model) ownedPaths(paths []string) []string {
+	if m.ownerFilter == "" || m.codeOwners == nil {
+		return paths
+	}
+	var owned []string
+	for _, p := range paths {
+		if m.codeOwners.IsOwnedBy(p, m.ownerFilter) {
+			owned = append(owned, p)
+		}
+	}
+	return owned
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"var/internal/git"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// commandLogHelp lists the keys of the command log
+const commandLogHelp = "[j/k: scroll | d/u: half page | esc/`: close]"
+
+// OpenCommandLog shows the log of git commands run, as --debug does at startup
+func (m *Model) OpenCommandLog() {
+	vp := viewport.New(max(m.width-2, 1), max(m.height-3, 1))
+	m.commandLog = &vp
+	m.refreshCommandLog()
+	m.commandLog.GotoBottom()
+}
+
+// refreshCommandLog renders the commands run so far, keeping the scroll position
+func (m *Model) refreshCommandLog() {
+	m.commandLog.Width = max(m.width-2, 1)
+	m.commandLog.Height = max(m.height-3, 1)
+	m.commandLog.SetContent(renderCommands(m.gitService.Commands(), m.commandLog.Width))
+}
+
+// renderCommands lists commands one per line: start time, duration, exit status and
+// the command line, with failures in red and their error below them
+func renderCommands(records []git.CommandRecord, width int) string {
+	if len(records) == 0 {
+		return SubtitleStyle.Render("No git commands run yet")
+	}
+	var b strings.Builder
+	for _, r := range records {
+		line := fmt.Sprintf("%s %9s  exit %-3d %s",
+			SubtitleStyle.Render(r.Start.Format("15:04:05.000")),
+			formatDuration(r.Duration),
+			r.ExitCode,
+			strings.Join(r.Args, " "))
+		if r.ExitCode != 0 {
+			line = lipgloss.NewStyle().Foreground(ColorError).Render(stripANSI(line))
+		}
+		b.WriteString(ansi.Truncate(line, width, "…") + "\n")
+		if r.ExitCode != 0 && r.Err != "" {
+			b.WriteString(ansi.Truncate(SubtitleStyle.Render("    "+strings.ReplaceAll(r.Err, "\n", " ")), width, "…") + "\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatDuration shows a command's run time in milliseconds, or seconds once it is longer
+func formatDuration(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+	}
+	return d.Round(10 * time.Millisecond).String()
+}
+
+// answerCommandLog scrolls the command log or closes it
+func (m *Model) answerCommandLog(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "`", "q":
+		m.commandLog = nil
+		return nil
+	case "ctrl+c":
+		return m.quit()
+	case "d":
+		m.commandLog.HalfViewDown()
+		return nil
+	case "u":
+		m.commandLog.HalfViewUp()
+		return nil
+	}
+	m.refreshCommandLog()
+	var cmd tea.Cmd
+	*m.commandLog, cmd = m.commandLog.Update(msg)
+	return cmd
+}
+
+// commandLogView draws the command log over the whole screen
+func (m Model) commandLogView() string {
+	vp := *m.commandLog
+	vp.SetContent(renderCommands(m.gitService.Commands(), vp.Width))
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("2")).
+		Render(vp.View())
+	help := ModeBadgeCommits.Render("GIT LOG") + " " + HelpStyle.Render(commandLogHelp)
+	return lipgloss.JoinVertical(lipgloss.Left, box, help)
+}
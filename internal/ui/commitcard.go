@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"strings"
+)
+
+// commitCard draws the header of git show output, its commit line, fields (Author, Date,
+// Merge) and message, as a card width columns wide. body is the message without its
+// indentation; when trailers were found in it, its last paragraph is left out for the
+// trailer section. The annotated tags in notes go above the message if they are this
+// commit's, and its git note goes last. It fails on a header without the commit line, like a stash's.
+func commitCard(header, body []string, trailers map[string][]string, notes tagNotes, note commitNote, width int) (string, bool) {
+	if len(header) == 0 {
+		return "", false
+	}
+	hash, ok := strings.CutPrefix(stripANSI(header[0]), "commit ")
+	if !ok {
+		return "", false
+	}
+	hash, refs, _ := strings.Cut(hash, " ")
+
+	// Fields run from the commit line to the blank line before the message
+	var keys, values []string
+	keyWidth := 0
+	for _, line := range header[1:] {
+		line = stripANSI(line)
+		key, value, found := strings.Cut(line, ":")
+		if !found || strings.HasPrefix(line, " ") {
+			break
+		}
+		keys = append(keys, key)
+		values = append(values, strings.TrimSpace(value))
+		keyWidth = max(keyWidth, len(key))
+	}
+
+	lines := []string{CardHashStyle.Render(hash)}
+	if refs != "" {
+		lines[0] += " " + CardRefsStyle.Render(refs)
+	}
+	for i, key := range keys {
+		style := SubtitleStyle
+		switch {
+		case key == "Author":
+			style = CardAuthorStyle
+		case strings.HasSuffix(key, "Date"):
+			style = CardDateStyle
+		}
+		lines = append(lines, SubtitleStyle.Render(key+strings.Repeat(" ", keyWidth-len(key)))+"  "+style.Render(values[i]))
+	}
+
+	if notes.hash == hash && len(notes.tags) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, tagSection(notes.tags)...)
+	}
+
+	message := trimBlankLines(body)
+	if len(trailers) > 0 {
+		// Trailers are the last paragraph
+		last := len(message)
+		for last > 0 && strings.TrimSpace(message[last-1]) != "" {
+			last--
+		}
+		message = trimBlankLines(message[:last])
+	}
+	if len(message) > 0 {
+		lines = append(lines, "", CardSubjectStyle.Render(message[0]))
+		lines = append(lines, message[1:]...)
+	}
+	if len(trailers) > 0 {
+		lines = append(lines, "")
+		lines = append(lines, trailerSection(trailers)...)
+	}
+	if note.hash == hash && note.text != "" {
+		lines = append(lines, "")
+		lines = append(lines, noteSection(note.text)...)
+	}
+
+	// The border takes a column on each side
+	card := CardStyle.Width(max(width-2, 20)).Render(strings.Join(lines, "\n"))
+	return card + "\n", true
+}
+
+// trimBlankLines drops the blank lines at either end
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// commitFormatRegex matches the placeholders of a commit list row template
+var commitFormatRegex = regexp.MustCompile(`\{(hash|date|author|subject)\}`)
+
+// commitFormatPart is a piece of a row template: literal text, or the field a
+// placeholder stands for
+type commitFormatPart struct {
+	text  string
+	field string
+}
+
+// parseCommitFormat splits a row template like "{hash} {date} {author} {subject}" into
+// its parts. Braces around anything but a known field are kept as text. An empty
+// template gives no parts, for the built-in layout.
+func parseCommitFormat(template string) []commitFormatPart {
+	if template == "" {
+		return nil
+	}
+	var parts []commitFormatPart
+	last := 0
+	for _, loc := range commitFormatRegex.FindAllStringSubmatchIndex(template, -1) {
+		if loc[0] > last {
+			parts = append(parts, commitFormatPart{text: template[last:loc[0]]})
+		}
+		parts = append(parts, commitFormatPart{field: template[loc[2]:loc[3]]})
+		last = loc[1]
+	}
+	if last < len(template) {
+		parts = append(parts, commitFormatPart{text: template[last:]})
+	}
+	return parts
+}
+
+// formatField returns the value of a placeholder for a commit, empty when the list
+// doesn't know it (reflog entries have no author, for instance)
+func formatField(field string, i CommitItem) string {
+	switch field {
+	case "hash":
+		if len(i.Hash) > 7 {
+			return i.Hash[:7]
+		}
+		return i.Hash
+	case "date":
+		if i.Date.IsZero() {
+			return ""
+		}
+		return i.Date.Format("2006-01-02")
+	case "author":
+		return i.Author
+	default:
+		return i.Message
+	}
+}
+
+// renderFormatted draws a commit from the row template, cut to the row's width like the
+// built-in layout, or continued on a second line when wrapping
+func (d commitItemDelegate) renderFormatted(w io.Writer, m list.Model, index int, i CommitItem) {
+	isSelected := index == m.Index()
+	width := m.Width()
+
+	bg := lipgloss.Color("#0066cc")
+	fg := lipgloss.Color("#ffffff")
+	selectedStyle := lipgloss.NewStyle().Foreground(fg).Background(bg)
+
+	var b strings.Builder
+	b.WriteString("  ")
+	for _, part := range d.format {
+		value := part.text
+		if part.field != "" {
+			value = formatField(part.field, i)
+		}
+		switch {
+		case value == "":
+		case isSelected && part.field == "hash":
+			b.WriteString(selectedStyle.Bold(true).Render(value))
+		case isSelected:
+			b.WriteString(selectedStyle.Render(value))
+		case part.field == "hash":
+			b.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(value))
+		case part.field == "date" || part.field == "author":
+			b.WriteString(SubtitleStyle.Render(value))
+		case part.field == "subject":
+			if i.Stash {
+				b.WriteString(StashBadgeStyle.Render(stashBadge) + " ")
+			}
+			b.WriteString(renderConventionalPrefix(value))
+		default:
+			b.WriteString(value)
+		}
+	}
+	row := b.String()
+
+	// Leave the two-column margin of the built-in layout, and room for the stat
+	maxWidth := max(width-2, 1)
+	stat := d.statLabel(i.Hash)
+	rowWidth := maxWidth
+	if stat != "" {
+		rowWidth = max(maxWidth-len(stat)-1, 1)
+	}
+	var rest string
+	if d.wrap && ansi.StringWidth(row) > rowWidth {
+		rest = "    " + ansi.Truncate(ansi.TruncateLeft(row, rowWidth, ""), max(maxWidth-4, 1), "…")
+		row = ansi.Truncate(row, rowWidth, "")
+	} else {
+		row = ansi.Truncate(row, rowWidth, "…")
+	}
+	if isSelected {
+		row = withStat(row, stat, maxWidth, &selectedStyle)
+	} else {
+		row = withStat(row, stat, maxWidth, nil)
+	}
+
+	if isSelected {
+		lineStyle := lipgloss.NewStyle().Width(width).Background(bg)
+		fmt.Fprint(w, lineStyle.Render(row))
+		if d.wrap {
+			fmt.Fprint(w, "\n"+lineStyle.Render(rest))
+		}
+		return
+	}
+	fmt.Fprint(w, row)
+	if d.wrap {
+		fmt.Fprint(w, "\n"+rest)
+	}
+}
@@ -3,31 +3,63 @@ package ui
 import (
 	"fmt"
 	"io"
+	"strings"
+	"time"
+
+	"var/internal/git"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
+// stashBadge marks the stashes in a file's history
+const stashBadge = "[stash]"
+
 // CommitItem represents a commit in the commit list
 type CommitItem struct {
 	Hash    string
 	Message string
+	Date    time.Time // Author date, zero when unknown
+	Stash   bool      // A stash interleaved into a file's history
+	Author  string    // Author name, empty when unknown
 }
 
 func (i CommitItem) FilterValue() string { return i.Message }
 
-type commitItemDelegate struct{}
+type commitItemDelegate struct {
+	wrap   bool               // Continue long subjects on a second line instead of truncating
+	format []commitFormatPart // Row template from the config, nil for hash and subject
 
-func (d commitItemDelegate) Height() int                             { return 1 }
+	stats map[string]git.CommitStat // Lines each commit adds and deletes, nil when hidden
+}
+
+func (d commitItemDelegate) Height() int {
+	if d.wrap {
+		return 2
+	}
+	return 1
+}
 func (d commitItemDelegate) Spacing() int                            { return 0 }
 func (d commitItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
 
 func (d commitItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
+	if header, ok := listItem.(dateHeaderItem); ok {
+		fmt.Fprint(w, SubtitleStyle.Bold(true).Render(" "+header.label))
+		if d.wrap {
+			fmt.Fprint(w, "\n")
+		}
+		return
+	}
 	i, ok := listItem.(CommitItem)
 	if !ok {
 		return
 	}
+	if d.format != nil {
+		d.renderFormatted(w, m, index, i)
+		return
+	}
 
 	isSelected := index == m.Index()
 	width := m.Width()
@@ -40,29 +72,68 @@ func (d commitItemDelegate) Render(w io.Writer, m list.Model, index int, listIte
 
 	// Truncate message to fit: width - 2 (indent) - 7 (hash) - 1 (space) - 2 (margin)
 	maxMsgLen := width - 12
+	stat := d.statLabel(i.Hash)
+	if stat != "" {
+		maxMsgLen -= len(stat) + 1
+	}
+	var badge string
+	if i.Stash {
+		badge = stashBadge + " "
+		maxMsgLen -= len(badge)
+	}
 	msg := i.Message
-	if maxMsgLen > 0 && len(msg) > maxMsgLen {
+	var rest string
+	if d.wrap {
+		msg, rest = wrapSubject(msg, maxMsgLen)
+	} else if maxMsgLen > 0 && ansi.StringWidth(msg) > maxMsgLen {
+		// Cut by display width, so wide characters neither split nor overflow the panel
 		if maxMsgLen > 3 {
-			msg = msg[:maxMsgLen-1] + "…"
+			msg = ansi.Truncate(msg, maxMsgLen, "…")
 		} else {
-			msg = msg[:maxMsgLen]
+			msg = ansi.Truncate(msg, maxMsgLen, "")
 		}
 	}
+	// The continuation line is indented to line up under the message
+	indent := strings.Repeat(" ", 2+len(hash)+1)
 
 	if isSelected {
 		bg := lipgloss.Color("#0066cc")
 		fg := lipgloss.Color("#ffffff")
 		hashStyle := lipgloss.NewStyle().Foreground(fg).Background(bg).Bold(true)
 		msgStyle := lipgloss.NewStyle().Foreground(fg).Background(bg)
-		line := fmt.Sprintf("  %s %s", hashStyle.Render(hash), msgStyle.Render(msg))
-		fmt.Fprint(w, lipgloss.NewStyle().Width(width).Background(bg).Render(line))
+		lineStyle := lipgloss.NewStyle().Width(width).Background(bg)
+		line := fmt.Sprintf("  %s %s", hashStyle.Render(hash), msgStyle.Render(badge+msg))
+		fmt.Fprint(w, lineStyle.Render(withStat(line, stat, width-2, &msgStyle)))
+		if d.wrap {
+			fmt.Fprint(w, "\n"+lineStyle.Render(indent+msgStyle.Render(rest)))
+		}
 	} else {
 		hashStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("3")) // Yellow
-		line := fmt.Sprintf("  %s %s", hashStyle.Render(hash), msg)
-		fmt.Fprint(w, line)
+		if i.Stash {
+			badge = StashBadgeStyle.Render(stashBadge) + " "
+		}
+		line := fmt.Sprintf("  %s %s%s", hashStyle.Render(hash), badge, renderConventionalPrefix(msg))
+		fmt.Fprint(w, withStat(line, stat, width-2, nil))
+		if d.wrap {
+			fmt.Fprint(w, "\n"+indent+rest)
+		}
 	}
 }
 
+// wrapSubject splits a subject into a first line at most maxLen columns wide, broken at
+// a space where possible, and a second line truncated with … if it still doesn't fit
+func wrapSubject(subject string, maxLen int) (string, string) {
+	if maxLen <= 0 || ansi.StringWidth(subject) <= maxLen {
+		return subject, ""
+	}
+	first := ansi.Truncate(subject, maxLen, "")
+	if i := strings.LastIndex(first, " "); i > 0 {
+		first = first[:i]
+	}
+	rest := strings.TrimLeft(subject[len(first):], " ")
+	return first, ansi.Truncate(rest, maxLen, "…")
+}
+
 // CommitList wraps a bubbles/list for commit selection
 type CommitList struct {
 	list      list.Model
@@ -70,6 +141,22 @@ type CommitList struct {
 	height    int
 	isFocused bool
 	label     string
+
+	// The commits, with day headers between them when grouping by date; rows maps each
+	// commit to its position among the list's items
+	items       []CommitItem
+	groupByDate bool
+	rows        []int
+
+	window listWindow // Scrolling set by zz/zt/zb
+
+	delegate commitItemDelegate // Row layout: wrapping and the configured template
+
+	// Type-to-jump: typed text moves the selection to the next matching commit
+	jumping    bool
+	jumpQuery  string
+	jumpOrigin int  // Selection when the jump started, restored on esc
+	jumpMiss   bool // Whether the query matches nothing
 }
 
 func NewCommitList(width, height int) CommitList {
@@ -91,11 +178,45 @@ func NewCommitList(width, height int) CommitList {
 }
 
 func (c *CommitList) SetItems(items []CommitItem) {
-	listItems := make([]list.Item, len(items))
+	c.items = items
+	listItems := make([]list.Item, 0, len(items))
+	c.rows = make([]int, len(items))
+	now := time.Now()
+	prev := ""
 	for i, item := range items {
-		listItems[i] = item
+		if c.groupByDate {
+			if label := dateGroupLabel(item.Date, now); label != "" && label != prev {
+				listItems = append(listItems, dateHeaderItem{label: label})
+				prev = label
+			}
+		}
+		c.rows[i] = len(listItems)
+		listItems = append(listItems, item)
 	}
 	c.list.SetItems(listItems)
+	c.window.reset()
+}
+
+// SetGroupByDate shows or hides day headers between the commits, keeping the selection
+func (c *CommitList) SetGroupByDate(group bool) {
+	selected := c.SelectedIndex()
+	c.groupByDate = group
+	c.SetItems(c.items)
+	c.SelectIndex(selected)
+}
+
+// skipHeader moves the selection off a day header, onward in the direction it was moving
+func (c *CommitList) skipHeader(step int) {
+	if _, ok := c.list.SelectedItem().(dateHeaderItem); !ok {
+		return
+	}
+	idx := c.list.Index() + step
+	if idx < 0 || idx >= len(c.list.Items()) {
+		idx = c.list.Index() - step
+	}
+	if idx >= 0 && idx < len(c.list.Items()) {
+		c.list.Select(idx)
+	}
 }
 
 func (c *CommitList) SetSize(width, height int) {
@@ -112,34 +233,166 @@ func (c *CommitList) IsFocused() bool {
 	return c.isFocused
 }
 
+// SetWrap switches between truncating long subjects and wrapping them onto a second line
+func (c *CommitList) SetWrap(wrap bool) {
+	c.delegate.wrap = wrap
+	c.list.SetDelegate(c.delegate)
+}
+
+// SetFormat lays rows out by a template of {hash}, {date}, {author} and {subject}
+// placeholders; an empty template keeps the hash and subject layout
+func (c *CommitList) SetFormat(template string) {
+	c.delegate.format = parseCommitFormat(template)
+	c.list.SetDelegate(c.delegate)
+}
+
 func (c *CommitList) SetTitle(title string) {
 	c.label = title
 	c.list.Title = title
 }
 
 func (c *CommitList) SelectedItem() *CommitItem {
-	item := c.list.SelectedItem()
-	if item == nil {
+	ci, ok := c.list.SelectedItem().(CommitItem)
+	if !ok {
 		return nil
 	}
-	ci := item.(CommitItem)
 	return &ci
 }
 
+// SelectedIndex returns the selected commit's position among the commits, not counting headers
 func (c *CommitList) SelectedIndex() int {
-	return c.list.Index()
+	row := c.list.Index()
+	for i, r := range c.rows {
+		if r >= row {
+			return i
+		}
+	}
+	return row
 }
 
+// SelectIndex selects the commit at index among the commits
 func (c *CommitList) SelectIndex(index int) {
+	if index >= 0 && index < len(c.rows) {
+		index = c.rows[index]
+	}
 	c.list.Select(index)
 }
 
+// StartJump begins type-to-jump; keys go to the query until enter or esc
+func (c *CommitList) StartJump() {
+	c.jumping = true
+	c.jumpQuery = ""
+	c.jumpOrigin = c.list.Index()
+	c.jumpMiss = false
+	c.updateJumpTitle()
+}
+
+// IsJumping reports whether type-to-jump is capturing keys
+func (c *CommitList) IsJumping() bool {
+	return c.jumping
+}
+
+func (c *CommitList) stopJump() {
+	c.jumping = false
+	c.list.Title = c.label
+}
+
+func (c *CommitList) updateJumpTitle() {
+	title := c.label + " /" + c.jumpQuery
+	if c.jumpMiss {
+		title += " (no match)"
+	}
+	c.list.Title = title
+}
+
+// jumpMatches reports whether a commit's hash starts with, or its message contains, the query
+func jumpMatches(item CommitItem, query string) bool {
+	query = strings.ToLower(query)
+	return strings.HasPrefix(strings.ToLower(item.Hash), query) ||
+		strings.Contains(strings.ToLower(item.Message), query)
+}
+
+// jumpTo selects the first match searching from start in the given direction, wrapping around
+func (c *CommitList) jumpTo(start, step int) {
+	items := c.list.Items()
+	c.jumpMiss = c.jumpQuery != ""
+	for n := 0; n < len(items) && c.jumpQuery != ""; n++ {
+		idx := ((start+n*step)%len(items) + len(items)) % len(items)
+		if item, ok := items[idx].(CommitItem); ok && jumpMatches(item, c.jumpQuery) {
+			c.list.Select(idx)
+			c.jumpMiss = false
+			break
+		}
+	}
+	c.updateJumpTitle()
+}
+
+// updateJump edits the query: enter keeps the selection, esc restores it,
+// ctrl+n/ctrl+p move to the next/previous match
+func (c *CommitList) updateJump(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		c.stopJump()
+	case tea.KeyEsc:
+		c.list.Select(c.jumpOrigin)
+		c.stopJump()
+	case tea.KeyCtrlN:
+		c.jumpTo(c.list.Index()+1, 1)
+	case tea.KeyCtrlP:
+		c.jumpTo(c.list.Index()-1, -1)
+	case tea.KeyBackspace:
+		if query := []rune(c.jumpQuery); len(query) > 0 {
+			c.jumpQuery = string(query[:len(query)-1])
+		}
+		c.list.Select(c.jumpOrigin)
+		c.jumpTo(c.jumpOrigin, 1)
+	case tea.KeyRunes, tea.KeySpace:
+		c.jumpQuery += string(msg.Runes)
+		c.jumpTo(c.list.Index(), 1)
+	}
+}
+
 func (c *CommitList) Update(msg tea.Msg) (CommitList, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && c.jumping {
+		c.updateJump(keyMsg)
+		return *c, nil
+	}
+	prev := c.list.Index()
+	defer func() {
+		// Day headers can't be selected: step over them the way the selection moved
+		step := 1
+		if c.list.Index() < prev {
+			step = -1
+		}
+		c.skipHeader(step)
+		c.window.follow(&c.list)
+	}()
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && halfPage(&c.list, keyMsg.String()) {
+		return *c, nil
+	}
 	var cmd tea.Cmd
 	c.list, cmd = c.list.Update(msg)
 	return *c, cmd
 }
 
+// halfPage moves a list's selection half a page for ctrl+d/ctrl+u, as d/u scroll the
+// diff view, reporting whether the key was one of them
+func halfPage(l *list.Model, key string) bool {
+	var step int
+	switch key {
+	case "ctrl+d":
+		step = max(l.Paginator.PerPage/2, 1)
+	case "ctrl+u":
+		step = -max(l.Paginator.PerPage/2, 1)
+	default:
+		return false
+	}
+	if n := len(l.VisibleItems()); n > 0 {
+		l.Select(min(max(l.Index()+step, 0), n-1))
+	}
+	return true
+}
+
 func (c *CommitList) View() string {
 	style := lipgloss.NewStyle().
 		Width(c.width).
@@ -150,5 +403,10 @@ func (c *CommitList) View() string {
 		style = style.BorderForeground(lipgloss.Color("2"))
 	}
 
-	return style.Render(c.list.View())
+	return style.Render(c.window.view(c.list))
+}
+
+// Recenter scrolls the list so the selection sits at where: "center", "top" or "bottom"
+func (c *CommitList) Recenter(where string) {
+	c.window.place(&c.list, where)
 }
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestCommitRowsFitWidth(t *testing.T) {
+	subjects := []string{
+		"fix: handle empty diffs",
+		"修复提交列表中的宽字符截断问题以及换行时的对齐",
+		"日本語のコミットメッセージがパネルからはみ出さないようにする",
+		"한국어 커밋 메시지 줄바꿈 테스트 한국어 커밋 메시지",
+		"🎉🚀✨ release the thing 🐛🔥 with emoji everywhere 👍👍👍",
+		"feat: 混在 mixed ASCII and 漢字 in one subject that runs long",
+	}
+	for _, width := range []int{20, 33, 40, 61} {
+		for _, wrap := range []bool{false, true} {
+			c := NewCommitList(width, 10)
+			c.SetWrap(wrap)
+			items := make([]CommitItem, len(subjects))
+			for i, subject := range subjects {
+				items[i] = CommitItem{Hash: "0123456789abcdef", Message: subject}
+			}
+			c.SetItems(items)
+			for index := range items {
+				var b bytes.Buffer
+				c.delegate.Render(&b, c.list, index, items[index])
+				for _, line := range strings.Split(b.String(), "\n") {
+					if got := ansi.StringWidth(line); got > width {
+						t.Errorf("width %d, wrap %v: row %q is %d columns wide",
+							width, wrap, stripANSI(line), got)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestWrapSubject(t *testing.T) {
+	tests := []struct {
+		subject     string
+		maxLen      int
+		first, rest string
+	}{
+		{"short", 10, "short", ""},
+		{"two words", 5, "two", "words"},
+		{"宽字符不会被切成两半", 7, "宽字符", "不会被…"},
+		{"🎉🚀✨ party time", 6, "🎉🚀✨", "party…"},
+		{"混在 mixed 漢字", 8, "混在", "mixed …"},
+	}
+	for _, tt := range tests {
+		first, rest := wrapSubject(tt.subject, tt.maxLen)
+		if first != tt.first || rest != tt.rest {
+			t.Errorf("wrapSubject(%q, %d) = %q, %q; want %q, %q",
+				tt.subject, tt.maxLen, first, rest, tt.first, tt.rest)
+		}
+		if ansi.StringWidth(first) > tt.maxLen || ansi.StringWidth(rest) > tt.maxLen {
+			t.Errorf("wrapSubject(%q, %d) = %q, %q; wider than %d columns",
+				tt.subject, tt.maxLen, first, rest, tt.maxLen)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// commitNote is the git note attached to a commit, shown in its description card
+type commitNote struct {
+	hash string
+	text string
+}
+
+type commitNoteMsg struct {
+	note commitNote
+}
+
+// loadCommitNote fetches the note of the commit whose description is shown, once per commit
+func (m *Model) loadCommitNote() tea.Cmd {
+	if !m.diffView.showDescription {
+		return nil
+	}
+	hash, _ := decoratedTags(m.diffView.RawContent())
+	if hash == "" || hash == m.diffView.commitNote.hash {
+		return nil
+	}
+	return func() tea.Msg {
+		text, _ := m.gitService.GetCommitNote(hash)
+		return commitNoteMsg{note: commitNote{hash: hash, text: text}}
+	}
+}
+
+// SetCommitNote sets the note shown in the description card of its commit
+func (d *DiffView) SetCommitNote(note commitNote) {
+	d.commitNote = note
+	if d.showDescription {
+		d.updateContent()
+	}
+}
+
+// noteSection shows a note under a heading, indented like the tag messages
+func noteSection(text string) []string {
+	section := []string{TrailerHeaderStyle.Render("Notes")}
+	for _, line := range strings.Split(text, "\n") {
+		section = append(section, "    "+line)
+	}
+	return section
+}
@@ -0,0 +1,48 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// nextCommitRef returns a branch or tag pointing at the selected commit, the next one
+// each time while the same commit stays selected, and a label giving its place among
+// them when there are several
+func (m *Model) nextCommitRef() (string, string, bool) {
+	if m.repoView != viewCommits || m.commitIndex >= len(m.commits) {
+		return "", "", false
+	}
+	commit := m.commits[m.commitIndex]
+	if len(commit.Refs) == 0 {
+		return "", "", false
+	}
+	if commit.Hash != m.commitRefHash {
+		m.commitRefHash, m.commitRefTurn = commit.Hash, 0
+	}
+	turn := m.commitRefTurn % len(commit.Refs)
+	m.commitRefTurn = turn + 1
+	ref := commit.Refs[turn]
+	if len(commit.Refs) == 1 {
+		return ref, ref, true
+	}
+	return ref, fmt.Sprintf("%s (%d/%d)", ref, turn+1, len(commit.Refs)), true
+}
+
+// gotoCommitRef lists the commits from a branch or tag on the selected commit
+func (m *Model) gotoCommitRef() tea.Cmd {
+	ref, label, ok := m.nextCommitRef()
+	if !ok {
+		return m.setStatus("No branch or tag on this commit")
+	}
+	return tea.Batch(m.showHistoryFrom(ref), m.setStatus("Commits from "+label))
+}
+
+// copyCommitRef copies the name of a branch or tag on the selected commit
+func (m *Model) copyCommitRef() tea.Cmd {
+	ref, label, ok := m.nextCommitRef()
+	if !ok {
+		return m.setStatus("No branch or tag on this commit")
+	}
+	return m.copyToClipboard(ref, label)
+}
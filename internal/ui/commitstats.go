@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// pendingStat marks a commit whose stat has been asked for, so it isn't asked for again
+var pendingStat = git.CommitStat{Additions: -1}
+
+type commitStatsMsg struct {
+	stats map[string]git.CommitStat
+}
+
+// toggleCommitStats shows or hides how many lines each commit in the list adds and deletes
+func (m *Model) toggleCommitStats() {
+	if m.commitStats != nil {
+		m.commitStats = nil
+	} else {
+		m.commitStats = map[string]git.CommitStat{}
+		m.commitStatsDue = true
+	}
+	m.commitList.SetStats(m.commitStats)
+}
+
+// takeCommitStats fetches the stats of the listed commits that don't have one yet, once
+// the list's items have changed while stats are shown. The list grows a page at a time,
+// so each fetch covers the new page.
+func (m *Model) takeCommitStats() tea.Cmd {
+	if !m.commitStatsDue || m.commitStats == nil {
+		return nil
+	}
+	m.commitStatsDue = false
+	var hashes []string
+	for _, hash := range m.commitList.Hashes() {
+		if _, ok := m.commitStats[hash]; !ok {
+			m.commitStats[hash] = pendingStat
+			hashes = append(hashes, hash)
+		}
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		// A file's whole history is listed at once, so keep each command line to a page
+		stats := make(map[string]git.CommitStat, len(hashes))
+		for start := 0; start < len(hashes); start += commitPageSize {
+			batch, err := m.gitService.GetCommitStats(hashes[start:min(start+commitPageSize, len(hashes))])
+			if err == nil {
+				maps.Copy(stats, batch)
+			}
+		}
+		return commitStatsMsg{stats: stats}
+	}
+}
+
+// applyCommitStats caches the fetched stats while stats are shown
+func (m *Model) applyCommitStats(msg commitStatsMsg) {
+	if m.commitStats == nil {
+		return
+	}
+	maps.Copy(m.commitStats, msg.stats)
+}
+
+// SetStats shows each commit's added and deleted lines at the end of its row, from stats
+// as they are filled in; nil hides them
+func (c *CommitList) SetStats(stats map[string]git.CommitStat) {
+	c.delegate.stats = stats
+	c.list.SetDelegate(c.delegate)
+}
+
+// Hashes returns the hashes of the listed commits
+func (c *CommitList) Hashes() []string {
+	hashes := make([]string, len(c.items))
+	for i, item := range c.items {
+		hashes[i] = item.Hash
+	}
+	return hashes
+}
+
+// statLabel returns a commit's stat as "+42 -13", empty while it loads or when it
+// changes no lines, as for a merge
+func (d commitItemDelegate) statLabel(hash string) string {
+	stat, ok := d.stats[hash]
+	if !ok || stat.Additions < 0 || stat.Additions+stat.Deletions == 0 {
+		return ""
+	}
+	return fmt.Sprintf("+%d -%d", stat.Additions, stat.Deletions)
+}
+
+// withStat right-aligns a stat label at the end of a row width columns wide, in the
+// selected row's colors or in green and red
+func withStat(row, label string, width int, selected *lipgloss.Style) string {
+	if label == "" {
+		return row
+	}
+	pad := strings.Repeat(" ", max(width-ansi.StringWidth(row)-len(label), 1))
+	if selected != nil {
+		return row + selected.Render(pad+label)
+	}
+	added, deleted, _ := strings.Cut(label, " ")
+	return row + pad + lipgloss.NewStyle().Foreground(ColorSuccess).Render(added) + " " +
+		lipgloss.NewStyle().Foreground(ColorError).Render(deleted)
+}
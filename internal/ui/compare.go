@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type fileCompareMsg struct {
+	specA, specB string
+	content      string
+	err          error
+}
+
+// promptCompare asks for two files to diff, each as path or path@rev, starting from the current file
+func (m *Model) promptCompare() tea.Cmd {
+	value := ""
+	if m.currentFile != "" {
+		value = shellQuote(m.currentFile) + " "
+	}
+	m.textInput.SetValue(value)
+	m.textInput.CursorEnd()
+	m.textInput.Placeholder = `a.go "b c.go@HEAD~3"`
+	m.textInput.Focus()
+	m.textInputMode = "compare"
+	return textinput.Blink
+}
+
+// loadFileCompare diffs the two files named in the prompt
+func (m *Model) loadFileCompare(value string) tea.Cmd {
+	specs, ok := splitQuoted(value)
+	if !ok || len(specs) != 2 {
+		return m.setStatus("Enter two files: path[@rev] path[@rev], quoting paths with spaces")
+	}
+	return func() tea.Msg {
+		content, err := m.gitService.GetArbitraryDiff(specs[0], specs[1])
+		return fileCompareMsg{specA: specs[0], specB: specs[1], content: content, err: err}
+	}
+}
+
+// splitQuoted splits value into words at spaces as a shell would, so the words can be
+// single- or double-quoted or have backslash-escaped characters; it fails on an
+// unclosed quote
+func splitQuoted(value string) ([]string, bool) {
+	var words []string
+	var word strings.Builder
+	var quote rune
+	inWord, escaped := false, false
+	for _, r := range value {
+		switch {
+		case escaped:
+			word.WriteRune(r)
+			escaped = false
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\\':
+			escaped, inWord = true, true
+		case r == '"' || r == '\'':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, quote == 0 && !escaped
+}
+
+// applyFileCompare shows the comparison in the diff view until the next navigation
+func (m *Model) applyFileCompare(msg fileCompareMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Compare failed: %v", msg.err))
+	}
+	if !hasHunk(msg.content) {
+		return m.setStatus(fmt.Sprintf("%s and %s are identical", msg.specA, msg.specB))
+	}
+	m.diffView.SetBanner(fmt.Sprintf("Comparing %s → %s", msg.specA, msg.specB))
+	m.diffView.SetContent(msg.content)
+	return nil
+}
@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSplitQuoted(t *testing.T) {
+	tests := []struct {
+		value string
+		words []string
+		ok    bool
+	}{
+		{"a.go b.go@HEAD~3", []string{"a.go", "b.go@HEAD~3"}, true},
+		{"  a.go\t b.go  ", []string{"a.go", "b.go"}, true},
+		{`"my file.go" 'other file.go@main'`, []string{"my file.go", "other file.go@main"}, true},
+		{`docs/"read me".md@HEAD x`, []string{"docs/read me.md@HEAD", "x"}, true},
+		{`my\ file.go b.go`, []string{"my file.go", "b.go"}, true},
+		{shellQuote("it's here.go") + " b.go", []string{"it's here.go", "b.go"}, true},
+		{`"" b.go`, []string{"", "b.go"}, true},
+		{`"unclosed b.go`, nil, false},
+		{`trailing\`, nil, false},
+	}
+	for _, tt := range tests {
+		words, ok := splitQuoted(tt.value)
+		if ok != tt.ok || (ok && !slices.Equal(words, tt.words)) {
+			t.Errorf("splitQuoted(%q) = %q, %v; want %q, %v", tt.value, words, ok, tt.words, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+)
+
+// conflictMarkerRegex matches a line left by a merge conflict, without its diff prefix
+var conflictMarkerRegex = regexp.MustCompile(`^(?:<{7}|\|{7}|>{7})(?: |$)|^={7}$`)
+
+// conflictMarkerColor is the SGR style marker lines are drawn in (bold magenta)
+const conflictMarkerColor = "1;35"
+
+// isConflictMarker reports whether a diff line (with its +, - or space prefix) is a conflict marker
+func isConflictMarker(line string) bool {
+	return len(line) > 1 && strings.ContainsRune("+- ", rune(line[0])) && conflictMarkerRegex.MatchString(line[1:])
+}
+
+// fileConflictMarker returns the text of a full-file line (number, tab, text) when it
+// is a conflict marker
+func fileConflictMarker(line string) (string, bool) {
+	_, text, ok := strings.Cut(stripANSI(line), "\t")
+	return text, ok && conflictMarkerRegex.MatchString(text)
+}
+
+// markFileConflicts colors the conflict markers of full-file content as diffs color them
+func markFileConflicts(content string) string {
+	if !strings.Contains(content, "=======") {
+		return content
+	}
+	rows := strings.Split(content, "\n")
+	for i, row := range rows {
+		if text, ok := fileConflictMarker(row); ok {
+			// Keep the number and any fold marker before it as they are
+			number := row[:strings.Index(row, "\t")+1]
+			rows[i] = number + "\x1b[" + conflictMarkerColor + "m" + text + "\x1b[0m"
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+// conflictPositions returns the line indices where conflict regions start: each <<<<<<<
+// marker, or the first marker of a region whose start is outside the diff. fullFile
+// reads the lines as full-file content rather than diff lines.
+func conflictPositions(content string, fullFile bool) []int {
+	if !strings.Contains(content, "=======") {
+		return nil
+	}
+	var positions []int
+	open := false
+	for i, line := range strings.Split(content, "\n") {
+		var marker string
+		if fullFile {
+			text, ok := fileConflictMarker(line)
+			if !ok {
+				continue
+			}
+			marker = text
+		} else {
+			line = stripANSI(line)
+			if !isConflictMarker(line) {
+				continue
+			}
+			marker = line[1:]
+		}
+		switch marker[0] {
+		case '<':
+			positions = append(positions, i)
+			open = true
+		case '>':
+			if !open {
+				positions = append(positions, i)
+			}
+			open = false
+		default:
+			if !open {
+				positions = append(positions, i)
+				open = true
+			}
+		}
+	}
+	return positions
+}
+
+// jumpToNextConflict scrolls to the next conflict region below the top of the view
+func (d *DiffView) jumpToNextConflict() {
+	offset := d.viewport.YOffset
+	for _, pos := range d.conflictPositions {
+		if pos > offset {
+			d.viewport.SetYOffset(pos)
+			return
+		}
+	}
+}
+
+// jumpToPrevConflict scrolls to the previous conflict region above the top of the view
+func (d *DiffView) jumpToPrevConflict() {
+	offset := d.viewport.YOffset
+	for i := len(d.conflictPositions) - 1; i >= 0; i-- {
+		if d.conflictPositions[i] < offset {
+			d.viewport.SetYOffset(d.conflictPositions[i])
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestConflictPositions(t *testing.T) {
+	tests := []struct {
+		name     string
+		lines    []string
+		fullFile bool
+		want     []int
+	}{
+		{
+			"diff",
+			[]string{"@@ -1,5 +1,5 @@", " a", "+<<<<<<< HEAD", "+ours", "+=======", "+theirs", "+>>>>>>> topic", " b"},
+			false,
+			[]int{2},
+		},
+		{
+			"diff starting inside a region",
+			[]string{"@@ -4,3 +4,3 @@", "+ours", "+=======", "+theirs", "+>>>>>>> topic"},
+			false,
+			[]int{2},
+		},
+		{
+			"full file",
+			[]string{"     1\ta", "     2\t<<<<<<< HEAD", "     3\tours", "     4\t=======", "     5\ttheirs", "     6\t>>>>>>> topic",
+				"     7\tb", "     8\t<<<<<<< HEAD", "     9\t=======", "    10\t>>>>>>> other"},
+			true,
+			[]int{1, 7},
+		},
+		{
+			"full file lines that only look like markers",
+			[]string{"     1\t // <<<<<<< HEAD", "     2\t========", "     3\t=======x"},
+			true,
+			nil,
+		},
+		{
+			"diff lines read as full file",
+			[]string{"@@ -1,3 +1,3 @@", "+<<<<<<< HEAD", "+=======", "+>>>>>>> topic"},
+			true,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := conflictPositions(strings.Join(tt.lines, "\n"), tt.fullFile); !slices.Equal(got, tt.want) {
+				t.Errorf("conflictPositions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarkFileConflicts(t *testing.T) {
+	content := "     1\ta\n     2\t<<<<<<< HEAD\n     3\t=======\n     4\t>>>>>>> topic"
+	marked := markFileConflicts(content)
+	if stripANSI(marked) != content {
+		t.Errorf("markFileConflicts changed the text:\n%s", stripANSI(marked))
+	}
+	rows := strings.Split(marked, "\n")
+	if rows[0] != "     1\ta" {
+		t.Errorf("plain line changed to %q", rows[0])
+	}
+	for _, row := range rows[1:] {
+		if !strings.Contains(row, "\t\x1b["+conflictMarkerColor+"m") {
+			t.Errorf("marker line %q is not colored", row)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conventionalRegex matches a conventional-commit prefix like "feat(ui)!: "
+var conventionalRegex = regexp.MustCompile(`^([a-zA-Z]+)(\([^)]*\))?!?: `)
+
+// conventionalColors assigns a color to each recognized commit type
+var conventionalColors = map[string]lipgloss.Color{
+	"feat":     lipgloss.Color("2"),
+	"fix":      lipgloss.Color("1"),
+	"perf":     lipgloss.Color("5"),
+	"refactor": lipgloss.Color("6"),
+	"docs":     lipgloss.Color("4"),
+	"test":     lipgloss.Color("3"),
+	"build":    lipgloss.Color("8"),
+	"ci":       lipgloss.Color("8"),
+	"chore":    lipgloss.Color("8"),
+	"style":    lipgloss.Color("8"),
+	"revert":   lipgloss.Color("1"),
+}
+
+// parseConventionalType returns the conventional-commit type of a message and the
+// length of its prefix, or an empty type if the message doesn't follow the convention
+func parseConventionalType(message string) (string, int) {
+	m := conventionalRegex.FindStringSubmatchIndex(message)
+	if m == nil {
+		return "", 0
+	}
+	typ := strings.ToLower(message[m[2]:m[3]])
+	if _, ok := conventionalColors[typ]; !ok {
+		return "", 0
+	}
+	// Exclude the trailing space from the prefix
+	return typ, m[1] - 1
+}
+
+// renderConventionalPrefix colors the type prefix of a commit message
+func renderConventionalPrefix(msg string) string {
+	typ, n := parseConventionalType(msg)
+	if typ == "" {
+		return msg
+	}
+	style := lipgloss.NewStyle().Foreground(conventionalColors[typ]).Bold(true)
+	return style.Render(msg[:n]) + msg[n:]
+}
+
+// conventionalTypes lists the commit types present in commits, sorted
+func conventionalTypes(commits []git.Commit) []string {
+	seen := make(map[string]bool)
+	var types []string
+	for _, c := range commits {
+		if typ, _ := parseConventionalType(c.Message); typ != "" && !seen[typ] {
+			seen[typ] = true
+			types = append(types, typ)
+		}
+	}
+	sort.Strings(types)
+	return types
+}
+
+// cycleTypeFilter steps the commit list through the commit types present, then back to all
+func (m *Model) cycleTypeFilter() tea.Cmd {
+	types := conventionalTypes(m.allCommits)
+	if len(types) == 0 {
+		return m.setStatus("No conventional commits to filter")
+	}
+	next := types[0]
+	for i, typ := range types {
+		if typ == m.typeFilter {
+			next = ""
+			if i+1 < len(types) {
+				next = types[i+1]
+			}
+		}
+	}
+	m.typeFilter = next
+	m.commitIndex = 0
+	m.applyTypeFilter()
+	return m.loadFilesForCurrentCommit
+}
+
+// applyTypeFilter fills the commit list with the loaded commits matching the type filter
+func (m *Model) applyTypeFilter() {
+	m.commits = m.filterByType(m.allCommits)
+	m.populateCommitList(m.commits)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(m.commitIndex)
+}
+
+// filterByType returns the commits matching the type filter
+func (m *Model) filterByType(commits []git.Commit) []git.Commit {
+	if m.typeFilter == "" {
+		return commits
+	}
+	var filtered []git.Commit
+	for _, c := range commits {
+		if typ, _ := parseConventionalType(c.Message); typ == m.typeFilter {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
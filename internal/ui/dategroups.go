@@ -0,0 +1,35 @@
+package ui
+
+import (
+	"time"
+)
+
+// dateHeaderItem is a non-selectable row naming the day of the commits below it
+type dateHeaderItem struct {
+	label string
+}
+
+func (i dateHeaderItem) FilterValue() string { return "" }
+
+// dateGroupLabel names the day t falls on relative to now: "Today", "Yesterday", the
+// weekday within the last week, and the date before that. Zero times have no label.
+func dateGroupLabel(t, now time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	t = t.In(now.Location())
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, now.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch days := int(today.Sub(day).Hours() / 24); {
+	case days <= 0:
+		return "Today"
+	case days == 1:
+		return "Yesterday"
+	case days < 7:
+		return t.Weekday().String()
+	case t.Year() == now.Year():
+		return t.Format("Mon Jan 2")
+	default:
+		return t.Format("Mon Jan 2, 2006")
+	}
+}
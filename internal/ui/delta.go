@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// deltaRender caches delta's rendering of a diff at a width, so scrolling and
+// re-rendering the same content doesn't run delta again
+type deltaRender struct {
+	input  string
+	width  int
+	output string
+	err    error
+}
+
+// pendingDelta is a delta run waiting to be started in the background, for the content
+// of generation gen
+type pendingDelta struct {
+	gen   int
+	input string
+	width int
+}
+
+// deltaRenderedMsg carries delta's rendering of the content of generation gen
+type deltaRenderedMsg struct {
+	gen    int
+	render deltaRender
+}
+
+// deltaAvailable reports whether delta is on PATH
+func deltaAvailable() bool {
+	_, err := exec.LookPath("delta")
+	return err == nil
+}
+
+// deltaRendering returns delta's rendering of a diff sized to the viewport once it has
+// run. Until then, it queues the run and reports false, as it does when delta failed.
+func (d *DiffView) deltaRendering(diff string) (string, bool) {
+	width := d.viewport.Width
+	if d.deltaCache.input == diff && d.deltaCache.width == width {
+		return d.deltaCache.output, d.deltaCache.err == nil
+	}
+	d.pendingDelta = &pendingDelta{gen: d.renderGen, input: diff, width: width}
+	return "", false
+}
+
+// runDelta pipes a diff through delta at a width
+func runDelta(diff string, width int) deltaRender {
+	cmd := exec.Command("delta", "--paging=never", fmt.Sprintf("--width=%d", width))
+	cmd.Stdin = strings.NewReader(stripANSI(diff))
+	output, err := cmd.Output()
+	return deltaRender{input: diff, width: width, output: strings.TrimRight(string(output), "\n"), err: err}
+}
+
+// applyDelta caches a finished delta run and shows it, unless the content changed since
+func (d *DiffView) applyDelta(msg deltaRenderedMsg) {
+	d.deltaCache = msg.render
+	if msg.gen == d.renderGen {
+		d.updateContent()
+	}
+}
+
+// SetDelta switches between delta and the built-in renderer for diffs
+func (d *DiffView) SetDelta(on bool) {
+	d.useDelta = on
+	d.updateContent()
+}
+
+// UsesDelta reports whether diffs are rendered with delta
+func (d *DiffView) UsesDelta() bool {
+	return d.useDelta
+}
+
+// toggleDiffRenderer switches the diff view between the built-in renderer and delta
+func (m *Model) toggleDiffRenderer() tea.Cmd {
+	if m.diffView.UsesDelta() {
+		m.diffView.SetDelta(false)
+		return m.setStatus("Rendering diffs with the built-in renderer")
+	}
+	if !deltaAvailable() {
+		return m.setStatus("delta not found on PATH")
+	}
+	m.diffView.SetDelta(true)
+	return m.setStatus("Rendering diffs with delta")
+}
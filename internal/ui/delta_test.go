@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDeltaRendersInBackground(t *testing.T) {
+	// A stand-in delta that counts its runs and prints a marker
+	dir := t.TempDir()
+	runs := filepath.Join(dir, "runs")
+	script := "#!/bin/sh\ncat >/dev/null\necho run >>" + runs + "\necho DELTA OUTPUT\n"
+	if err := os.WriteFile(filepath.Join(dir, "delta"), []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	d := NewDiffView(80, 20)
+	d.SetDelta(true)
+	d.SetContent("diff --git a/f b/f\n--- a/f\n+++ b/f\n@@ -1 +1 @@\n-old\n+new")
+	if strings.Contains(d.RenderedContent(), "DELTA") {
+		t.Fatal("delta ran while setting the content")
+	}
+	if !strings.Contains(stripANSI(d.RenderedContent()), "+new") {
+		t.Fatalf("built-in rendering not shown while delta runs: %q", d.RenderedContent())
+	}
+
+	cmd := d.takePendingRender()
+	if cmd == nil {
+		t.Fatal("no delta run queued")
+	}
+	msg, ok := cmd().(deltaRenderedMsg)
+	if !ok {
+		t.Fatalf("queued command returned %T, want deltaRenderedMsg", cmd())
+	}
+	d.applyDelta(msg)
+	if got := d.RenderedContent(); got != "DELTA OUTPUT" {
+		t.Fatalf("rendered content after delta = %q", got)
+	}
+
+	// Re-rendering the same content at the same width uses the cached output
+	d.updateContent()
+	if d.takePendingRender() != nil || d.RenderedContent() != "DELTA OUTPUT" {
+		t.Error("re-render ran delta again instead of using its cached output")
+	}
+	if out, _ := os.ReadFile(runs); strings.Count(string(out), "run") != 1 {
+		t.Errorf("delta ran %d times, want 1", strings.Count(string(out), "run"))
+	}
+
+	// A result for content that has since been replaced is cached but not shown
+	d.SetContent("diff --git a/g b/g\n--- a/g\n+++ b/g\n@@ -1 +1 @@\n-x\n+y")
+	d.applyDelta(msg)
+	if strings.Contains(d.RenderedContent(), "DELTA") {
+		t.Error("stale delta output replaced the new content")
+	}
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// trailerRegex matches git trailer lines like "Signed-off-by: Name <email>"
+var trailerRegex = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9-]*):\s+(.+)$`)
+
+// parseTrailers extracts git trailers from the last paragraph of a commit body.
+// The paragraph only counts as trailers if every line in it is a "Key: value" pair.
+func parseTrailers(body string) map[string][]string {
+	paragraphs := strings.Split(strings.TrimSpace(body), "\n\n")
+	last := strings.TrimSpace(paragraphs[len(paragraphs)-1])
+	if last == "" || len(paragraphs) < 2 {
+		return nil
+	}
+
+	trailers := make(map[string][]string)
+	for _, line := range strings.Split(last, "\n") {
+		m := trailerRegex.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			return nil
+		}
+		trailers[m[1]] = append(trailers[m[1]], m[2])
+	}
+	return trailers
+}
+
+// renderDescription restyles the commit header of git show output as a card fitting
+// width, moving trailers out of the message body into their own section and adding the
+// commit's annotated tags and git note. A header without the commit line is kept as is
+// apart from the trailers.
+func renderDescription(content string, notes tagNotes, note commitNote, width int) string {
+	lines := strings.Split(content, "\n")
+
+	// The header runs until the first diff line
+	end := len(lines)
+	for i, line := range lines {
+		stripped := stripANSI(line)
+		if strings.HasPrefix(stripped, "diff --git") || strings.HasPrefix(stripped, "@@") {
+			end = i
+			break
+		}
+	}
+
+	// git show indents the message by four spaces
+	var msgIdx []int
+	var body []string
+	for i, line := range lines[:end] {
+		if strings.HasPrefix(line, "    ") || (len(msgIdx) > 0 && strings.TrimSpace(line) == "") {
+			msgIdx = append(msgIdx, i)
+			body = append(body, strings.TrimPrefix(line, "    "))
+		}
+	}
+	trailers := parseTrailers(strings.Join(body, "\n"))
+	if card, ok := commitCard(lines[:end], body, trailers, notes, note, width); ok {
+		return card + "\n" + strings.Join(lines[end:], "\n")
+	}
+	if len(trailers) == 0 {
+		return content
+	}
+
+	// Drop the trailer lines (and blank lines before them) from the message
+	trailerCount := 0
+	for _, values := range trailers {
+		trailerCount += len(values)
+	}
+	cut := len(msgIdx)
+	for n := 0; cut > 0 && n < trailerCount; cut-- {
+		if strings.TrimSpace(lines[msgIdx[cut-1]]) != "" {
+			n++
+		}
+	}
+	for cut > 0 && strings.TrimSpace(lines[msgIdx[cut-1]]) == "" {
+		cut--
+	}
+	removeFrom, removeTo := msgIdx[cut], msgIdx[len(msgIdx)-1]+1
+
+	section := append([]string{""}, trailerSection(trailers)...)
+	section = append(section, "")
+
+	var result []string
+	result = append(result, lines[:removeFrom]...)
+	result = append(result, section...)
+	result = append(result, lines[removeTo:]...)
+	return strings.Join(result, "\n")
+}
+
+// trailerSection lists trailers under a heading, sorted by key
+func trailerSection(trailers map[string][]string) []string {
+	keys := make([]string, 0, len(trailers))
+	for key := range trailers {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	section := []string{TrailerHeaderStyle.Render("Trailers")}
+	for _, key := range keys {
+		for _, value := range trailers[key] {
+			section = append(section, "    "+TrailerKeyStyle.Render(key+":")+" "+value)
+		}
+	}
+	return section
+}
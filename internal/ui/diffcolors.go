@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"var/internal/config"
+)
+
+// wordHighlight is the pair of SGR codes that start and end the highlight of the changed
+// words in a modified line
+type wordHighlight struct {
+	on, off string
+}
+
+// reverseVideo is the default word highlight
+var reverseVideo = wordHighlight{on: "7", off: "27"}
+
+// diffColorScheme holds the SGR codes the built-in renderer draws diff lines in
+type diffColorScheme struct {
+	added, removed string
+	context        string // Empty leaves context lines in the terminal's color
+	addedWords     wordHighlight
+	removedWords   wordHighlight
+}
+
+// defaultDiffColors is the scheme used where the config doesn't set a color
+var defaultDiffColors = diffColorScheme{
+	added:        "32",
+	removed:      "31",
+	addedWords:   reverseVideo,
+	removedWords: reverseVideo,
+}
+
+// parseDiffColors applies the configured diff colors over the defaults; colors that
+// can't be read keep their default
+func parseDiffColors(cfg config.DiffColors) diffColorScheme {
+	colors := defaultDiffColors
+	if sgr, ok := sgrColor(cfg.Added, false); ok {
+		colors.added = sgr
+	}
+	if sgr, ok := sgrColor(cfg.Removed, false); ok {
+		colors.removed = sgr
+	}
+	if sgr, ok := sgrColor(cfg.Context, false); ok {
+		colors.context = sgr
+	}
+	if sgr, ok := sgrColor(cfg.AddedHighlight, true); ok {
+		colors.addedWords = wordHighlight{on: sgr, off: "49"}
+	}
+	if sgr, ok := sgrColor(cfg.RemovedHighlight, true); ok {
+		colors.removedWords = wordHighlight{on: sgr, off: "49"}
+	}
+	return colors
+}
+
+// sgrColor turns a configured color into the SGR code setting it as the foreground, or
+// the background: "0" to "15" are the terminal's own palette, "16" to "255" the
+// 256-color one and "#rrggbb" truecolor
+func sgrColor(value string, background bool) (string, bool) {
+	base := 30
+	if background {
+		base = 40
+	}
+	if len(value) == 7 && value[0] == '#' {
+		rgb, err := strconv.ParseUint(value[1:], 16, 32)
+		if err != nil {
+			return "", false
+		}
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, rgb>>16, rgb>>8&0xff, rgb&0xff), true
+	}
+	n, err := strconv.Atoi(value)
+	switch {
+	case err != nil || n < 0 || n > 255:
+		return "", false
+	case n < 8:
+		return strconv.Itoa(base + n), true
+	case n < 16:
+		// The bright colors
+		return strconv.Itoa(base + 60 + n - 8), true
+	default:
+		return fmt.Sprintf("%d;5;%d", base+8, n), true
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/viewport"
@@ -23,9 +24,62 @@ type DiffView struct {
 	inFileMode      bool   // Whether in single-file mode
 	viewMode        int    // Current view mode (0=diff, 1=context, 2=full, 3=blame)
 	rawContent      string // Raw diff content before line numbers
+	renderedContent string // Content as shown in the viewport (with line numbers)
 	showDescription bool   // Whether to show commit description (default false)
 	hunkPositions   []int  // Line positions of @@ hunk headers in rendered content
 	sourceIndicator string // Source mode indicator (e.g., "REFLOG", "S:\"term\"", "L:func")
+	banner          string // Notice pinned above the content (e.g., "File deleted in this commit")
+
+	// Two versions in columns instead of the diff (nil when inactive)
+	sideBySide *sideBySide
+
+	// Line positions where regions of committed conflict markers start, for { and }
+	conflictPositions []int
+
+	// Show carriage returns as a visible marker instead of dropping them
+	showLineEndings bool
+
+	// Show lines over longLineLimit in full instead of shortened
+	expandLongLines bool
+
+	// Folds of the full-file view, from each one's first file line to its last, and
+	// the first lines of those closed
+	foldRegions map[int]int
+	closedFolds map[int]bool
+
+	// Which line numbers the gutter shows and where, and whether it is hidden for now
+	gutter     GutterMode
+	hideGutter bool
+
+	// The colors the built-in renderer draws diff lines in
+	colors diffColorScheme
+
+	// Column labels pinned above the content while it has a line number gutter
+	gutterHeader string
+
+	// Full-file lines picked out as one author's, dimming the rest (nil when inactive)
+	authorLines map[int]bool
+
+	// Show hunks whose context overlaps or meets as one block
+	joinHunks bool
+
+	// Annotated tags of the commit, shown in its description card
+	tagNotes tagNotes
+
+	// Git note of the commit, shown in its description card
+	commitNote commitNote
+
+	// Content as laid out in the viewport, one line per rendered line, before the gutter is added
+	shownContent string
+
+	// Render diffs with delta instead of the built-in renderer
+	useDelta   bool
+	deltaCache deltaRender
+
+	// Long diffs finish rendering in the background; renderGen discards renders of replaced content
+	renderGen     int
+	pendingRender *pendingRender
+	pendingDelta  *pendingDelta
 }
 
 func NewDiffView(width, height int) DiffView {
@@ -38,6 +92,7 @@ func NewDiffView(width, height int) DiffView {
 		height:      height,
 		isFocused:   false,
 		commitIndex: -1,
+		colors:      defaultDiffColors,
 	}
 }
 
@@ -45,14 +100,71 @@ func (d *DiffView) SetSize(width, height int) {
 	d.width = width
 	d.height = height
 	d.viewport.Width = width - 2  // Account for borders
-	d.viewport.Height = height - 2 // Account for borders only
+	d.layoutViewport()
+	if d.sideBySide != nil || d.gutter == GutterRight || d.useDelta {
+		// The layout depends on the width
+		d.updateContent()
+	}
+}
+
+// layoutViewport sizes the viewport to the space left by the header, banner and footer
+func (d *DiffView) layoutViewport() {
+	height := d.height - 2 // Account for borders only
+	if d.banner != "" {
+		height--
+	}
+	if d.gutterHeader != "" {
+		height--
+	}
+	d.viewport.Height = height
+}
+
+// setGutterHeader pins column labels above the content, or removes them when empty
+func (d *DiffView) setGutterHeader(header string) {
+	if header != d.gutterHeader {
+		d.gutterHeader = header
+		d.layoutViewport()
+	}
+}
+
+// SetBanner pins a notice above the content; an empty string removes it
+func (d *DiffView) SetBanner(banner string) {
+	d.banner = banner
+	d.layoutViewport()
 }
 
 func (d *DiffView) SetContent(content string) {
 	d.rawContent = content
+	d.sideBySide = nil
+	d.closedFolds = nil
 	d.updateContent()
 }
 
+// SetGutterMode changes the line number gutter layout
+func (d *DiffView) SetGutterMode(mode GutterMode) {
+	d.gutter = mode
+	d.updateContent()
+}
+
+// SetColors changes the colors the built-in renderer draws diff lines in
+func (d *DiffView) SetColors(colors diffColorScheme) {
+	d.colors = colors
+	d.updateContent()
+}
+
+// SetJoinHunks joins hunks whose context overlaps or meets in diffs
+func (d *DiffView) SetJoinHunks(on bool) {
+	d.joinHunks = on
+	d.updateContent()
+}
+
+// SetSideBySide shows two versions in columns until the next SetContent
+func (d *DiffView) SetSideBySide(sbs sideBySide) {
+	d.sideBySide = &sbs
+	d.updateContent()
+	d.viewport.GotoTop()
+}
+
 // stripDiffHeader removes the commit description and diff metadata from
 // git show output, keeping only from the first hunk header (@@ ...) onwards.
 func stripDiffHeader(content string) string {
@@ -66,20 +178,181 @@ func stripDiffHeader(content string) string {
 	return content
 }
 
+// modeLineRegex matches "old mode 100644" / "new mode 100755" diff header lines
+var modeLineRegex = regexp.MustCompile(`^(old|new) mode (\d+)`)
+
+// describeModeChange returns a readable note for a file mode change in the diff header,
+// or an empty string if the mode did not change
+func describeModeChange(content string) string {
+	var oldMode, newMode string
+	for _, line := range strings.Split(content, "\n") {
+		stripped := stripANSI(line)
+		if strings.HasPrefix(stripped, "@@") {
+			break
+		}
+		if m := modeLineRegex.FindStringSubmatch(stripped); m != nil {
+			if m[1] == "old" {
+				oldMode = m[2]
+			} else {
+				newMode = m[2]
+			}
+		}
+	}
+	if oldMode == "" || newMode == "" {
+		return ""
+	}
+
+	note := fmt.Sprintf("mode changed: %s → %s", oldMode, newMode)
+	switch {
+	case oldMode == "100644" && newMode == "100755":
+		note += " (added execute bit)"
+	case oldMode == "100755" && newMode == "100644":
+		note += " (removed execute bit)"
+	case newMode == "120000":
+		note += " (now a symlink)"
+	case oldMode == "120000":
+		note += " (no longer a symlink)"
+	}
+	return "\x1b[33m" + note + "\x1b[0m"
+}
+
+// hasHunk reports whether content contains a hunk header
+func hasHunk(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if strings.HasPrefix(stripANSI(line), "@@") {
+			return true
+		}
+	}
+	return false
+}
+
 func (d *DiffView) updateContent() {
-	content := d.rawContent
+	d.renderGen++
+	d.pendingRender = nil
+	d.pendingDelta = nil
+	d.conflictPositions = nil
+	d.setGutterHeader("")
+	if d.sideBySide != nil {
+		d.hunkPositions = nil
+		rendered := renderSideBySide(*d.sideBySide, d.viewport.Width)
+		d.rawContent = stripANSI(rendered)
+		d.setViewportContent(rendered)
+		return
+	}
+	content := normalizeLineEndings(d.rawContent, d.showLineEndings)
 	if d.viewMode == 3 {
 		// Blame mode: content already has its own formatting
 		d.hunkPositions = nil
-		d.viewport.SetContent(content)
+		d.shownContent = content
+		d.setViewportContent(content)
 		return
 	}
+	if d.useDelta && !d.showDescription && hasHunk(content) {
+		// delta lays out the diff itself. It runs in the background, with the built-in
+		// renderer showing the diff until it finishes, and for good if it fails.
+		if rendered, ok := d.deltaRendering(content); ok {
+			d.hunkPositions = nil
+			d.shownContent = ""
+			d.setViewportContent(rendered)
+			return
+		}
+	}
+	gutter := d.gutter
+	if d.hideGutter {
+		gutter = GutterNone
+	}
 	if !d.showDescription {
+		// Mode changes live in the stripped header, so carry them over
+		modeNote := describeModeChange(content)
 		content = stripDiffHeader(content)
+		if modeNote != "" {
+			if hasHunk(content) {
+				content = modeNote + "\n" + content
+			} else {
+				content = modeNote
+			}
+		}
+	} else {
+		content = renderDescription(content, d.tagNotes, d.commitNote, gutter.contentWidth(d.viewport.Width))
+	}
+	if d.joinHunks && d.viewMode < 2 {
+		content = joinAdjacentHunks(content)
+	}
+	d.foldRegions = nil
+	if d.viewMode == 2 && !d.showDescription {
+		content = d.foldFullFile(content)
+		content = markFileConflicts(content)
+		if d.authorLines != nil {
+			content = markAuthorLines(content, d.authorLines)
+		}
+	}
+	if !d.expandLongLines {
+		content = elideLongLines(content)
+	}
+	d.shownContent = content
+	if isCombinedDiff(content) {
+		rendered, hunkPos := renderCombinedDiff(content, gutter, d.colors, d.viewport.Width)
+		d.hunkPositions = hunkPos
+		d.setViewportContent(rendered)
+		return
+	}
+	if hasHunk(content) {
+		d.setGutterHeader(gutter.header(d.viewport.Width))
+		d.conflictPositions = conflictPositions(content, false)
+	} else if d.viewMode == 2 {
+		d.conflictPositions = conflictPositions(content, true)
 	}
-	rendered, hunkPos := addLineNumbers(content)
+	if d.renderIncrementally(content, gutter) {
+		return
+	}
+	rendered, hunkPos := addLineNumbers(content, gutter, d.colors, d.viewport.Width)
 	d.hunkPositions = hunkPos
-	d.viewport.SetContent(rendered)
+	d.setViewportContent(rendered)
+}
+
+func (d *DiffView) setViewportContent(content string) {
+	d.renderedContent = content
+	d.viewport.SetContent(content)
+}
+
+// RenderedContent returns the content as rendered in the viewport, including line numbers and ANSI styling
+func (d *DiffView) RenderedContent() string {
+	return d.renderedContent
+}
+
+// RawContent returns the content as loaded, before line numbers are added
+func (d *DiffView) RawContent() string {
+	return d.rawContent
+}
+
+// crMarker stands in for a carriage return when line endings are shown
+const crMarker = "␍"
+
+// normalizeLineEndings removes carriage returns, which would otherwise move the
+// cursor and corrupt the gutter, or replaces them with a visible marker. A line
+// whose only change is its ending then gets the marker highlighted as the change.
+func normalizeLineEndings(content string, showMarker bool) string {
+	if !strings.Contains(content, "\r") {
+		return content
+	}
+	if showMarker {
+		return strings.ReplaceAll(content, "\r", crMarker)
+	}
+	return strings.ReplaceAll(content, "\r", "")
+}
+
+// ToggleLineEndings switches between hiding carriage returns and marking them
+func (d *DiffView) ToggleLineEndings() bool {
+	d.showLineEndings = !d.showLineEndings
+	d.updateContent()
+	return d.showLineEndings
+}
+
+// ToggleLineNumbers hides or restores the line number gutter, reporting whether it is shown
+func (d *DiffView) ToggleLineNumbers() bool {
+	d.hideGutter = !d.hideGutter
+	d.updateContent()
+	return !d.hideGutter
 }
 
 func (d *DiffView) ToggleDescription() {
@@ -104,11 +377,18 @@ type diffBlock struct {
 	plusTexts  []string // stripped text (no ANSI) for each plus line
 	minusNums  []int    // old line numbers
 	plusNums   []int    // new line numbers
+
+	// "\ No newline at end of file" after the last minus or plus line, if any
+	minusMarker, plusMarker string
 }
 
-// highlightDiff applies reverse video to the changed portion between two lines.
+// highlightDiff highlights the changed portion between two lines with words.
 // baseColor is the ANSI color code for the line type (31=red, 32=green).
-func highlightDiff(thisText, otherText string, baseColor string) string {
+func highlightDiff(thisText, otherText string, baseColor string, words wordHighlight) string {
+	if len(thisText) > longLineLimit || len(otherText) > longLineLimit {
+		// Scanning huge lines is slow and a change inside one can't be seen anyway
+		return fmt.Sprintf("\x1b[%sm%s\x1b[0m", baseColor, thisText)
+	}
 	thisRunes := []rune(thisText)
 	otherRunes := []rune(otherText)
 
@@ -142,10 +422,9 @@ func highlightDiff(thisText, otherText string, baseColor string) string {
 	if changeStart > 0 {
 		b.WriteString(string(thisRunes[:changeStart]))
 	}
-	// Reverse video for changed portion
-	b.WriteString("\x1b[7m")
+	b.WriteString("\x1b[" + words.on + "m")
 	b.WriteString(string(thisRunes[changeStart:changeEnd]))
-	b.WriteString("\x1b[27m")
+	b.WriteString("\x1b[" + words.off + "m")
 	if suffixLen > 0 {
 		b.WriteString(string(thisRunes[changeEnd:]))
 	}
@@ -154,9 +433,10 @@ func highlightDiff(thisText, otherText string, baseColor string) string {
 }
 
 // flushBlock outputs buffered minus/plus lines with word-level highlighting
-func flushBlock(block *diffBlock, result *[]string) {
+func flushBlock(block *diffBlock, result *[]string, gutter GutterMode, colors diffColorScheme, width int) {
 	minCount := len(block.minusTexts)
 	plusCount := len(block.plusTexts)
+	added, removed := colors.added, colors.removed
 
 	// Pair lines: min(minus, plus) get highlighting
 	pairCount := minCount
@@ -168,47 +448,63 @@ func flushBlock(block *diffBlock, result *[]string) {
 	for i := 0; i < minCount; i++ {
 		text := block.minusTexts[i]
 		var rendered string
-		if i < pairCount {
+		if isConflictMarker(text) {
+			rendered = gutter.render(gutterNum(block.minusNums[i], removed), gutterAbsent, "\x1b["+conflictMarkerColor+"m"+text+"\x1b[0m", width)
+		} else if i < pairCount {
 			// Paired: apply word-level highlighting
 			// Skip the leading '-' for comparison, then prepend it back
 			thisContent := text[1:] // skip '-'
 			otherContent := block.plusTexts[i][1:] // skip '+'
-			highlighted := highlightDiff(thisContent, otherContent, "31")
-			rendered = fmt.Sprintf("\x1b[31m%4d\x1b[0m %4s │ \x1b[31m-\x1b[0m%s", block.minusNums[i], "", highlighted)
+			highlighted := highlightDiff(thisContent, otherContent, removed, colors.removedWords)
+			rendered = gutter.render(gutterNum(block.minusNums[i], removed), gutterAbsent, "\x1b["+removed+"m-\x1b[0m"+highlighted, width)
 		} else {
 			// Unpaired: normal red
-			rendered = fmt.Sprintf("\x1b[31m%4d\x1b[0m %4s │ \x1b[31m%s\x1b[0m", block.minusNums[i], "", text)
+			rendered = gutter.render(gutterNum(block.minusNums[i], removed), gutterAbsent, "\x1b["+removed+"m"+text+"\x1b[0m", width)
 		}
 		*result = append(*result, rendered)
 	}
+	if block.minusMarker != "" {
+		*result = append(*result, noNewlineMarker(block.minusMarker, gutter, width))
+	}
 
 	// Output all plus lines
 	for i := 0; i < plusCount; i++ {
 		text := block.plusTexts[i]
 		var rendered string
-		if i < pairCount {
+		if isConflictMarker(text) {
+			rendered = gutter.render(gutterAbsent, gutterNum(block.plusNums[i], added), "\x1b["+conflictMarkerColor+"m"+text+"\x1b[0m", width)
+		} else if i < pairCount {
 			// Paired: apply word-level highlighting
 			thisContent := text[1:] // skip '+'
 			otherContent := block.minusTexts[i][1:] // skip '-'
-			highlighted := highlightDiff(thisContent, otherContent, "32")
-			rendered = fmt.Sprintf("%4s \x1b[32m%4d\x1b[0m │ \x1b[32m+\x1b[0m%s", "", block.plusNums[i], highlighted)
+			highlighted := highlightDiff(thisContent, otherContent, added, colors.addedWords)
+			rendered = gutter.render(gutterAbsent, gutterNum(block.plusNums[i], added), "\x1b["+added+"m+\x1b[0m"+highlighted, width)
 		} else {
 			// Unpaired: normal green
-			rendered = fmt.Sprintf("%4s \x1b[32m%4d\x1b[0m │ \x1b[32m%s\x1b[0m", "", block.plusNums[i], text)
+			rendered = gutter.render(gutterAbsent, gutterNum(block.plusNums[i], added), "\x1b["+added+"m"+text+"\x1b[0m", width)
 		}
 		*result = append(*result, rendered)
 	}
+	if block.plusMarker != "" {
+		*result = append(*result, noNewlineMarker(block.plusMarker, gutter, width))
+	}
 
 	// Reset block
 	block.minusTexts = block.minusTexts[:0]
 	block.plusTexts = block.plusTexts[:0]
 	block.minusNums = block.minusNums[:0]
 	block.plusNums = block.plusNums[:0]
+	block.minusMarker, block.plusMarker = "", ""
+}
+
+// noNewlineMarker renders "\ No newline at end of file" dimmed, without line numbers
+func noNewlineMarker(text string, gutter GutterMode, width int) string {
+	return gutter.render(gutterBlank, gutterBlank, "\x1b[2m"+text+"\x1b[0m", width)
 }
 
-// addLineNumbers prepends line numbers to diff content and returns hunk header positions.
+// addLineNumbers adds a line number gutter to diff content and returns hunk header positions.
 // It buffers consecutive -/+ lines to apply word-level inline diff highlighting.
-func addLineNumbers(content string) (string, []int) {
+func addLineNumbers(content string, gutter GutterMode, colors diffColorScheme, width int) (string, []int) {
 	if content == "" {
 		return content, nil
 	}
@@ -235,7 +531,7 @@ func addLineNumbers(content string) (string, []int) {
 		if matches := hunkHeaderRegex.FindStringSubmatch(stripped); matches != nil {
 			// Flush any pending block
 			if collectingMinus || collectingPlus {
-				flushBlock(&block, &result)
+				flushBlock(&block, &result, gutter, colors, width)
 				collectingMinus = false
 				collectingPlus = false
 			}
@@ -243,29 +539,41 @@ func addLineNumbers(content string) (string, []int) {
 			fmt.Sscanf(matches[2], "%d", &newLine)
 			inHunk = true
 			hunkPositions = append(hunkPositions, len(result))
-			result = append(result, fmt.Sprintf("%4s %4s │ %s", "", "", line))
+			result = append(result, gutter.render(gutterBlank, gutterBlank, line, width))
 			continue
 		}
 
 		if !inHunk {
-			result = append(result, fmt.Sprintf("%4s %4s │ %s", "", "", line))
+			result = append(result, gutter.render(gutterBlank, gutterBlank, line, width))
 			continue
 		}
 
 		if len(stripped) == 0 {
 			// Empty line in diff context — flush any block
 			if collectingMinus || collectingPlus {
-				flushBlock(&block, &result)
+				flushBlock(&block, &result, gutter, colors, width)
 				collectingMinus = false
 				collectingPlus = false
 			}
-			result = append(result, fmt.Sprintf("%4d %4d │ %s", oldLine, newLine, line))
+			result = append(result, gutter.render(gutterNum(oldLine, "2"), gutterNum(newLine, ""), line, width))
 			oldLine++
 			newLine++
+		} else if stripped[0] == '\\' {
+			// "\ No newline at end of file" belongs to the line before it and has no number.
+			// It stays with a changed line without ending the block, so the old and new last
+			// lines of a file still pair up for word highlighting.
+			switch {
+			case collectingPlus:
+				block.plusMarker = stripped
+			case collectingMinus:
+				block.minusMarker = stripped
+			default:
+				result = append(result, noNewlineMarker(stripped, gutter, width))
+			}
 		} else if stripped[0] == '-' {
 			if collectingPlus {
 				// New minus after plus means end of block, flush
-				flushBlock(&block, &result)
+				flushBlock(&block, &result, gutter, colors, width)
 				collectingMinus = false
 				collectingPlus = false
 			}
@@ -289,11 +597,16 @@ func addLineNumbers(content string) (string, []int) {
 		} else {
 			// Context line — flush any pending block
 			if collectingMinus || collectingPlus {
-				flushBlock(&block, &result)
+				flushBlock(&block, &result, gutter, colors, width)
 				collectingMinus = false
 				collectingPlus = false
 			}
-			result = append(result, fmt.Sprintf("%4d %4d │ %s", oldLine, newLine, line))
+			if isConflictMarker(stripped) {
+				line = "\x1b[" + conflictMarkerColor + "m" + stripped + "\x1b[0m"
+			} else if colors.context != "" {
+				line = "\x1b[" + colors.context + "m" + stripped + "\x1b[0m"
+			}
+			result = append(result, gutter.render(gutterNum(oldLine, "2"), gutterNum(newLine, ""), line, width))
 			oldLine++
 			newLine++
 		}
@@ -301,7 +614,7 @@ func addLineNumbers(content string) (string, []int) {
 
 	// Flush any remaining block
 	if collectingMinus || collectingPlus {
-		flushBlock(&block, &result)
+		flushBlock(&block, &result, gutter, colors, width)
 	}
 
 	return strings.Join(result, "\n"), hunkPositions
@@ -329,7 +642,12 @@ func (d *DiffView) renderViewTabs() string {
 			parts = append(parts, ViewTabInactive.Render(tab))
 		}
 	}
-	return strings.Join(parts, " ")
+	// The source is the other half of what's shown, so it sits with the tabs even at its default
+	source := d.sourceIndicator
+	if source == "" {
+		source = "COMMITS"
+	}
+	return strings.Join(parts, " ") + "  " + SourceBadge.Render(source)
 }
 
 func (d *DiffView) SetSourceIndicator(indicator string) {
@@ -344,6 +662,23 @@ func (d *DiffView) IsFocused() bool {
 	return d.isFocused
 }
 
+// YOffset returns the current vertical scroll offset
+func (d *DiffView) YOffset() int {
+	return d.viewport.YOffset
+}
+
+// SetYOffset scrolls the viewport to the given line
+func (d *DiffView) SetYOffset(offset int) {
+	d.viewport.SetYOffset(offset)
+}
+
+// GotoFirstHunk scrolls to the first hunk header, if there is one
+func (d *DiffView) GotoFirstHunk() {
+	if len(d.hunkPositions) > 0 {
+		d.viewport.SetYOffset(d.hunkPositions[0])
+	}
+}
+
 // CommitIndex returns the current commit index (-1 for working copy)
 func (d *DiffView) CommitIndex() int {
 	return d.commitIndex
@@ -394,6 +729,12 @@ func (d *DiffView) Update(msg tea.Msg) (DiffView, tea.Cmd) {
 		case "N":
 			d.jumpToPrevHunk()
 			return *d, nil
+		case "}":
+			d.jumpToNextConflict()
+			return *d, nil
+		case "{":
+			d.jumpToPrevConflict()
+			return *d, nil
 		}
 	}
 
@@ -410,25 +751,33 @@ func (d *DiffView) View() string {
 		header = fmt.Sprintf("%s (working copy)", d.filePath)
 	}
 
-	// Add view mode tabs and source indicator when in file mode
+	// Add view mode tabs and the source they show when in file mode
 	if d.inFileMode {
-		if d.sourceIndicator != "" {
-			header = header + "  " + SourceBadge.Render(d.sourceIndicator)
-		}
-		tabs := d.renderViewTabs()
-		header = header + "   " + tabs
+		header = header + "   " + d.renderViewTabs()
+	}
+	if d.useDelta {
+		header = header + "  " + SubtitleStyle.Render("[delta]")
 	}
 
-	// Build footer with scroll percentage
+	// Build footer with scroll percentage, after the hunk at the top of the view if there are hunks
 	scrollPercent := d.viewport.ScrollPercent() * 100
 	footer := fmt.Sprintf("%.0f%%", scrollPercent)
+	if idx := d.currentHunkIndex(); idx >= 0 {
+		footer = fmt.Sprintf("hunk %d/%d  %s", idx+1, len(d.hunkPositions), footer)
+	}
 
-	content := lipgloss.JoinVertical(
-		lipgloss.Left,
-		lipgloss.NewStyle().Bold(true).Padding(0, 1).Render(header),
+	sections := []string{lipgloss.NewStyle().Bold(true).Padding(0, 1).Render(header)}
+	if d.banner != "" {
+		sections = append(sections, BannerStyle.Render(d.banner))
+	}
+	if d.gutterHeader != "" {
+		sections = append(sections, d.gutterHeader)
+	}
+	sections = append(sections,
 		d.viewport.View(),
 		lipgloss.NewStyle().Faint(true).Padding(0, 1).Render(footer),
 	)
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
 
 	style := lipgloss.NewStyle().
 		Width(d.width).
@@ -443,3 +792,123 @@ func (d *DiffView) View() string {
 
 	return style.Render(content)
 }
+
+// TopFileLine returns the 1-based line number, in the file version being shown, of the
+// first line at or below the top of the viewport that exists in that version
+func (d *DiffView) TopFileLine() (int, bool) {
+	n, _, ok := d.TopFileLineText()
+	return n, ok
+}
+
+// TopFileLineText is TopFileLine that also returns the line's plain text
+func (d *DiffView) TopFileLineText() (int, string, bool) {
+	if d.sideBySide != nil {
+		return 0, "", false
+	}
+	offset := d.viewport.YOffset
+	lines := strings.Split(d.shownContent, "\n")
+	if offset >= len(lines) {
+		return 0, "", false
+	}
+
+	switch d.viewMode {
+	case 3:
+		// Blame has one line per file line
+		text := stripANSI(lines[offset])
+		if parts := blameLineRegex.FindStringSubmatch(text); parts != nil {
+			text = parts[2]
+		}
+		return offset + 1, text, true
+	case 2:
+		// Full file lines carry their number before a tab
+		for _, line := range lines[offset:] {
+			if n, text, ok := fullFileLine(line); ok {
+				return n, text, true
+			}
+		}
+		return 0, "", false
+	}
+
+	// Diff: count new-side lines from the hunk header, skipping removed lines
+	newLine := 0
+	inHunk := false
+	for i, line := range lines {
+		stripped := stripANSI(line)
+		if m := hunkHeaderRegex.FindStringSubmatch(stripped); m != nil {
+			newLine, _ = strconv.Atoi(m[2])
+			inHunk = true
+			continue
+		}
+		if !inHunk || strings.HasPrefix(stripped, "-") || strings.HasPrefix(stripped, "\\") {
+			continue
+		}
+		if i >= offset {
+			// Drop the +/space marker
+			if stripped != "" {
+				stripped = stripped[1:]
+			}
+			return newLine, stripped, true
+		}
+		newLine++
+	}
+	return 0, "", false
+}
+
+// VisibleFileLines returns the first and last file line numbers on screen in full-file mode
+func (d *DiffView) VisibleFileLines() (int, int, bool) {
+	if d.viewMode != 2 || d.sideBySide != nil {
+		return 0, 0, false
+	}
+	lines := strings.Split(d.shownContent, "\n")
+	start := min(d.viewport.YOffset, len(lines))
+	end := min(start+d.viewport.Height, len(lines))
+	first, last := 0, 0
+	for _, line := range lines[start:end] {
+		if n, _, ok := fullFileLine(line); ok {
+			if first == 0 {
+				first = n
+			}
+			last = n
+		}
+	}
+	return first, last, first > 0
+}
+
+// currentHunkIndex returns the index of the hunk at the top of the viewport, or -1 if there are none
+func (d *DiffView) currentHunkIndex() int {
+	if len(d.hunkPositions) == 0 {
+		return -1
+	}
+	idx := 0
+	for i, pos := range d.hunkPositions {
+		if pos <= d.viewport.YOffset {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// CurrentHunk returns the plain-text lines (header included) of the hunk at the top of the viewport
+func (d *DiffView) CurrentHunk() []string {
+	target := d.currentHunkIndex()
+	if target < 0 {
+		return nil
+	}
+
+	var hunk []string
+	idx := -1
+	for _, line := range strings.Split(d.rawContent, "\n") {
+		stripped := stripANSI(line)
+		if strings.HasPrefix(stripped, "@@") {
+			idx++
+		} else if strings.HasPrefix(stripped, "diff --git") {
+			idx = -1
+		}
+		if idx == target {
+			hunk = append(hunk, stripped)
+		} else if idx > target {
+			break
+		}
+	}
+	return hunk
+}
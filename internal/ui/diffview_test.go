@@ -0,0 +1,45 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddLineNumbersNoNewlineAtEOF(t *testing.T) {
+	content := strings.Join([]string{
+		"@@ -1,2 +1,2 @@",
+		" keep",
+		"-return old",
+		`\ No newline at end of file`,
+		"+return new",
+		`\ No newline at end of file`,
+	}, "\n")
+	rendered, _ := addLineNumbers(content, GutterBoth, defaultDiffColors, 80)
+	lines := strings.Split(rendered, "\n")
+
+	var got []string
+	for _, line := range lines {
+		got = append(got, strings.TrimSpace(stripANSI(line)))
+	}
+	want := []string{
+		"│ @@ -1,2 +1,2 @@",
+		"1    1 │  keep",
+		"2    · │ -return old",
+		`│ \ No newline at end of file`,
+		"·    2 │ +return new",
+		`│ \ No newline at end of file`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("addLineNumbers lines:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// The old and new last lines still pair up, so only the changed word is highlighted
+	highlight := "\x1b[" + defaultDiffColors.removedWords.on + "m"
+	if !strings.Contains(lines[2], highlight+"old") {
+		t.Errorf("removed line %q has no word highlight on \"old\"", lines[2])
+	}
+	highlight = "\x1b[" + defaultDiffColors.addedWords.on + "m"
+	if !strings.Contains(lines[4], highlight+"new") {
+		t.Errorf("added line %q has no word highlight on \"new\"", lines[4])
+	}
+}
@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// saveRenderedView writes the rendered diff view to a temp file for sharing.
+// With withANSI false the escape codes are stripped so the file pastes cleanly.
+func (m *Model) saveRenderedView(withANSI bool) tea.Cmd {
+	content := m.diffView.RenderedContent()
+	if content == "" {
+		return m.setStatus("Nothing to save")
+	}
+
+	ext := "txt"
+	if withANSI {
+		ext = "ansi"
+	} else {
+		content = stripANSI(content)
+	}
+
+	hash := m.diffView.commitHash
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+	name := filepath.Base(m.currentFile)
+	if hash != "" {
+		name = hash + "-" + name
+	}
+	path := filepath.Join(os.TempDir(), fmt.Sprintf("var-%s.%s", name, ext))
+
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return m.setStatus(fmt.Sprintf("Save failed: %v", err))
+	}
+	return m.setStatus("Saved view to " + path)
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptFetch asks for a remote ref to fetch and review, such as a pull request's head
+func (m *Model) promptFetch() tea.Cmd {
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "[remote] ref, e.g. origin refs/pull/123/head"
+	m.textInput.Focus()
+	m.textInputMode = "fetch"
+	return textinput.Blink
+}
+
+// fetchAndReview fetches "[remote] ref" (remote defaults to origin) without touching the
+// working tree, then opens the PR view of the fetched ref against the default branch
+func (m *Model) fetchAndReview(value string) tea.Cmd {
+	fields := strings.Fields(value)
+	remote, ref := "origin", ""
+	switch len(fields) {
+	case 1:
+		ref = fields[0]
+	case 2:
+		remote, ref = fields[0], fields[1]
+	default:
+		return m.setStatus("Fetch needs a ref, optionally after a remote")
+	}
+	return m.trackOperation(fmt.Sprintf("Fetching %s from %s", ref, remote), func(op *Model) tea.Msg {
+		tracking, err := op.gitService.Fetch(remote, ref)
+		if err != nil {
+			return prViewLoadedMsg{err: fmt.Errorf("fetching %s: %w", ref, err)}
+		}
+		return op.loadPRView(tracking)()
+	})
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"fmt"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleFileStashes interleaves the stashes that touched the file into its history, or
+// takes them out again, keeping the selected entry where it's still listed
+func (m *Model) toggleFileStashes() tea.Cmd {
+	m.fileStashes = !m.fileStashes
+	status := "Stashes hidden from history"
+	if m.fileStashes {
+		status = "Stashes shown in history"
+	}
+	var selected string
+	if m.fileCommitIndex < len(m.fileCommits) {
+		selected = m.fileCommits[m.fileCommitIndex].Hash
+	}
+	load := func(op *Model) tea.Msg {
+		msg := op.loadFileCommits().(fileCommitsLoadedMsg)
+		msg.selected = selected
+		return msg
+	}
+	return tea.Batch(m.setStatus(status), m.trackOperation("Loading history", load))
+}
+
+// withStashes merges stashes into a file's commits by date, newest first
+func withStashes(commits, stashes []git.Commit) []git.Commit {
+	merged := make([]git.Commit, 0, len(commits)+len(stashes))
+	for len(commits) > 0 && len(stashes) > 0 {
+		if stashes[0].Date.After(commits[0].Date) {
+			merged = append(merged, stashes[0])
+			stashes = stashes[1:]
+		} else {
+			merged = append(merged, commits[0])
+			commits = commits[1:]
+		}
+	}
+	merged = append(merged, commits...)
+	return append(merged, stashes...)
+}
+
+// currentFileStash returns the stash selected in the file's history, if a stash is
+func (m *Model) currentFileStash() (git.Commit, bool) {
+	if m.sourceMode != sourceCommits || m.fileCommitIndex >= len(m.fileCommits) {
+		return git.Commit{}, false
+	}
+	c := m.fileCommits[m.fileCommitIndex]
+	return c, git.IsStashRef(c.Ref)
+}
+
+// loadFileStash shows what a stash changed in the file relative to the commit it was made on
+func (m *Model) loadFileStash(file string, stash git.Commit) tea.Msg {
+	diff, err := m.gitService.GetStashDiff(stash.Hash, file, git.StashBaseParent)
+	if err != nil {
+		return diffLoadedMsg{content: fmt.Sprintf("Error: %v", err)}
+	}
+	if diff == "" {
+		return diffLoadedMsg{content: "File unchanged by this stash"}
+	}
+	return diffLoadedMsg{content: diff, banner: "Stashed changes (" + stash.Ref + ")"}
+}
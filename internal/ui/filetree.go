@@ -28,7 +28,9 @@ type TreeItem struct {
 
 func (i TreeItem) FilterValue() string { return i.Node.Path }
 
-type treeItemDelegate struct{}
+type treeItemDelegate struct {
+	query string // Tree search query, highlighted in the names of matching files
+}
 
 func (d treeItemDelegate) Height() int                             { return 1 }
 func (d treeItemDelegate) Spacing() int                            { return 0 }
@@ -60,6 +62,8 @@ func (d treeItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	width := m.Width()
 	if len(label) > width-2 {
 		label = label[:width-2]
+	} else if d.query != "" && !node.IsDir && !isSelected {
+		label = indent + icon + highlightMatch(node.Name, d.query)
 	}
 
 	if isSelected {
@@ -83,6 +87,20 @@ type FileTree struct {
 	isFocused bool
 	allNodes  []TreeNode // full sorted tree (dirs + files)
 	expanded  map[string]bool
+
+	// Directories shallower than this are expanded when files load or the depth changes
+	expandDepth int
+	maxDepth    int // Deepest directory level in the tree
+
+	// Search (/): while typing, only the files matching the query and the directories
+	// holding them are shown, expanded. The title, selection and expanded directories
+	// from before the search are kept to restore.
+	searching      bool
+	searchQuery    string
+	searchHits     map[string]bool // Matching files and their directories, nil for none
+	searchTitle    string
+	searchOrigin   string
+	searchExpanded map[string]bool
 }
 
 func NewFileTree(width, height int) FileTree {
@@ -96,13 +114,19 @@ func NewFileTree(width, height int) FileTree {
 		Padding(0, 1)
 
 	return FileTree{
-		list:     l,
-		width:    width,
-		height:   height,
-		expanded: make(map[string]bool),
+		list:        l,
+		width:       width,
+		height:      height,
+		expanded:    make(map[string]bool),
+		expandDepth: 1,
 	}
 }
 
+// SetExpandDepth sets how many directory levels are expanded when files load
+func (ft *FileTree) SetExpandDepth(depth int) {
+	ft.expandDepth = max(depth, 0)
+}
+
 func (ft *FileTree) SetSize(width, height int) {
 	ft.width = width
 	ft.height = height
@@ -113,13 +137,42 @@ func (ft *FileTree) SetFocused(focused bool) {
 	ft.isFocused = focused
 }
 
+// SetSparse marks the tree as limited to a sparse checkout in its title
+func (ft *FileTree) SetSparse(sparse bool) {
+	ft.list.Title = "Tree"
+	if sparse {
+		ft.list.Title = "Tree (sparse)"
+	}
+}
+
+// SetRevision titles the tree with the commit it lists, when that isn't HEAD
+func (ft *FileTree) SetRevision(rev string) {
+	ft.list.Title = "Tree @ " + rev
+}
+
 // SetFiles builds the tree from a flat list of file paths
 func (ft *FileTree) SetFiles(paths []string) {
 	ft.allNodes = buildTreeNodes(paths)
+	ft.maxDepth = 0
+	for _, node := range ft.allNodes {
+		if node.IsDir {
+			ft.maxDepth = max(ft.maxDepth, node.Depth)
+		}
+	}
+	ft.applyExpandDepth()
+}
+
+// changeExpandDepth expands or collapses every directory one level at a time
+func (ft *FileTree) changeExpandDepth(delta int) {
+	ft.expandDepth = min(max(ft.expandDepth+delta, 0), ft.maxDepth+1)
+	ft.applyExpandDepth()
+}
+
+// applyExpandDepth expands exactly the directories shallower than expandDepth
+func (ft *FileTree) applyExpandDepth() {
 	ft.expanded = make(map[string]bool)
-	// Expand root-level directories by default
 	for _, node := range ft.allNodes {
-		if node.IsDir && node.Depth == 0 {
+		if node.IsDir && node.Depth < ft.expandDepth {
 			ft.expanded[node.Path] = true
 		}
 	}
@@ -144,6 +197,45 @@ func (ft *FileTree) IsSelectedDir() bool {
 	return item.(TreeItem).Node.IsDir
 }
 
+// IsExpanded reports whether a directory is open
+func (ft *FileTree) IsExpanded(dirPath string) bool {
+	return ft.expanded[dirPath]
+}
+
+// FileChildren returns the paths of the files directly inside a directory
+func (ft *FileTree) FileChildren(dirPath string) []string {
+	var paths []string
+	for _, node := range ft.allNodes {
+		if !node.IsDir && path.Dir(node.Path) == dirPath {
+			paths = append(paths, node.Path)
+		}
+	}
+	return paths
+}
+
+// DirSummary lists the immediate children of a directory
+func (ft *FileTree) DirSummary(dirPath string) string {
+	var dirs, files []string
+	for _, node := range ft.allNodes {
+		if path.Dir(node.Path) != dirPath {
+			continue
+		}
+		if node.IsDir {
+			dirs = append(dirs, node.Name+"/")
+		} else {
+			files = append(files, node.Name)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(dirPath + "/\n\n")
+	for _, name := range append(dirs, files...) {
+		b.WriteString("  " + name + "\n")
+	}
+	b.WriteString(fmt.Sprintf("\n%d directories, %d files\n", len(dirs), len(files)))
+	return b.String()
+}
+
 func (ft *FileTree) toggleExpand(dirPath string) {
 	ft.expanded[dirPath] = !ft.expanded[dirPath]
 	ft.rebuildVisibleItems()
@@ -185,7 +277,7 @@ func (ft *FileTree) rebuildVisibleItems() {
 		}
 		n := node
 		if n.IsDir {
-			n.Expanded = ft.expanded[n.Path]
+			n.Expanded = ft.expanded[n.Path] || ft.searchHits[n.Path]
 		}
 		if n.Path == selectedPath {
 			newSelectedIdx = len(items)
@@ -197,6 +289,9 @@ func (ft *FileTree) rebuildVisibleItems() {
 }
 
 func (ft *FileTree) isVisible(node TreeNode) bool {
+	if ft.searching && ft.searchQuery != "" {
+		return ft.searchHits[node.Path]
+	}
 	if node.Depth == 0 {
 		return true
 	}
@@ -212,6 +307,10 @@ func (ft *FileTree) isVisible(node TreeNode) bool {
 }
 
 func (ft *FileTree) Update(msg tea.Msg) (FileTree, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && ft.searching {
+		ft.updateSearch(keyMsg)
+		return *ft, nil
+	}
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -225,6 +324,12 @@ func (ft *FileTree) Update(msg tea.Msg) (FileTree, tea.Cmd) {
 		case "h":
 			ft.collapseSelected()
 			return *ft, nil
+		case "+":
+			ft.changeExpandDepth(1)
+			return *ft, nil
+		case "-":
+			ft.changeExpandDepth(-1)
+			return *ft, nil
 		}
 	}
 
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+)
+
+// firstChangeContext is how many lines are kept above the first change when scrolling to it
+const firstChangeContext = 3
+
+// firstChangedLine returns the first line of the file the commit changed, 0 if there is none
+func (m *Model) firstChangedLine(file, hash string) int {
+	diff, err := m.gitService.GetDiffAtCommitWithContext(file, hash, 0)
+	if err != nil {
+		return 0
+	}
+	for _, line := range strings.Split(diff, "\n") {
+		if matches := hunkHeaderRegex.FindStringSubmatch(stripANSI(line)); matches != nil {
+			// With no context, the new start is the first changed line (or the one before a deletion)
+			n, _ := strconv.Atoi(matches[2])
+			return max(n, 1)
+		}
+	}
+	return 0
+}
+
+// scrollToFirstChange scrolls newly loaded content to where the changes start: the
+// commit's first changed line in full-file mode, and with scrollToFirstChange set to
+// "all", the first hunk of diffs too
+func (m *Model) scrollToFirstChange(msg diffLoadedMsg) {
+	switch {
+	case m.config.ScrollToFirstChange == "off":
+	case msg.firstChange > 0:
+		m.diffView.SetYOffset(max(msg.firstChange-1-firstChangeContext, 0))
+	case m.config.ScrollToFirstChange == "all":
+		m.diffView.GotoFirstHunk()
+	}
+}
@@ -0,0 +1,207 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Fold markers shown before the line number of each line that starts a fold
+const (
+	foldOpen   = "▾"
+	foldClosed = "▸"
+)
+
+// fullFileLine splits a line of full-file content into its number and text, ignoring
+// a fold marker before the number
+func fullFileLine(line string) (int, string, bool) {
+	num, text, found := strings.Cut(stripANSI(line), "\t")
+	if !found {
+		return 0, "", false
+	}
+	num = strings.TrimLeft(num, foldOpen+foldClosed)
+	n, err := strconv.Atoi(strings.TrimSpace(num))
+	return n, text, err == nil
+}
+
+// indentWidth measures a line's leading whitespace, a tab counting as four columns
+func indentWidth(text string) int {
+	width := 0
+	for _, r := range text {
+		switch r {
+		case ' ':
+			width++
+		case '\t':
+			width += 4
+		default:
+			return width
+		}
+	}
+	return width
+}
+
+// foldRegions finds the blocks of full-file content by indentation: a line followed by
+// more indented lines starts a fold that runs to the last of them, leaving out blank
+// lines after it. It maps each fold's first line number to its last.
+func foldRegions(nums []int, texts []string) map[int]int {
+	type open struct{ line, indent int }
+	regions := make(map[int]int)
+	var stack []open
+	last := 0 // Last non-blank line seen
+	closeTo := func(indent int) {
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if last > top.line {
+				regions[top.line] = last
+			}
+		}
+	}
+	for i, text := range texts {
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		indent := indentWidth(text)
+		closeTo(indent)
+		stack = append(stack, open{line: nums[i], indent: indent})
+		last = nums[i]
+	}
+	closeTo(0)
+	return regions
+}
+
+// foldFullFile marks where folds start in full-file content and hides the lines of
+// closed ones, recording the folds for za. Content that isn't numbered file lines is
+// returned as is.
+func (d *DiffView) foldFullFile(content string) string {
+	lines := strings.Split(content, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	nums := make([]int, len(lines))
+	texts := make([]string, len(lines))
+	for i, line := range lines {
+		n, text, ok := fullFileLine(line)
+		if !ok {
+			return content
+		}
+		nums[i], texts[i] = n, text
+	}
+	d.foldRegions = foldRegions(nums, texts)
+
+	var b strings.Builder
+	hideTo := 0 // Last line of the closed fold being skipped
+	for i, line := range lines {
+		if nums[i] <= hideTo {
+			continue
+		}
+		end, starts := d.foldRegions[nums[i]]
+		switch {
+		case !starts:
+			b.WriteString(" " + line)
+		case d.closedFolds[nums[i]]:
+			hidden := fmt.Sprintf("⋯ %d lines", end-nums[i])
+			if end-nums[i] == 1 {
+				hidden = "⋯ 1 line"
+			}
+			b.WriteString(SubtitleStyle.Render(foldClosed) + line + " " + SubtitleStyle.Render(hidden))
+			hideTo = end
+		default:
+			b.WriteString(SubtitleStyle.Render(foldOpen) + line)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// foldAt returns the innermost fold holding file line n, or 0 if none does
+func (d *DiffView) foldAt(n int) int {
+	best := 0
+	for start, end := range d.foldRegions {
+		if start <= n && n <= end && start > best {
+			best = start
+		}
+	}
+	return best
+}
+
+// ToggleFold opens or closes the fold holding the top line of the full-file view
+func (d *DiffView) ToggleFold() bool {
+	n, ok := d.TopFileLine()
+	if d.viewMode != 2 || !ok {
+		return false
+	}
+	start := d.foldAt(n)
+	if start == 0 {
+		return false
+	}
+	if d.closedFolds == nil {
+		d.closedFolds = make(map[int]bool)
+	}
+	d.closedFolds[start] = !d.closedFolds[start]
+	d.updateContent()
+	d.scrollToFileLine(start)
+	return true
+}
+
+// SetAllFolds closes every fold in the full-file view, or opens them all
+func (d *DiffView) SetAllFolds(closed bool) bool {
+	if d.viewMode != 2 || d.foldRegions == nil {
+		return false
+	}
+	top, ok := d.TopFileLine()
+	d.closedFolds = make(map[int]bool)
+	if closed {
+		for start := range d.foldRegions {
+			d.closedFolds[start] = true
+		}
+	}
+	d.updateContent()
+	if ok {
+		// Keep the top line in view, or the outermost closed fold hiding it
+		for start, end := range d.foldRegions {
+			if d.closedFolds[start] && start < top && top <= end {
+				top = start
+			}
+		}
+		d.scrollToFileLine(top)
+	}
+	return true
+}
+
+// scrollToFileLine moves the full-file view so file line n, or the nearest line after
+// it, is at the top
+func (d *DiffView) scrollToFileLine(n int) {
+	for i, line := range strings.Split(d.shownContent, "\n") {
+		if num, _, ok := fullFileLine(line); ok && num >= n {
+			d.viewport.SetYOffset(i)
+			return
+		}
+	}
+}
+
+// foldKey runs the fold command for the key after z: za toggles the fold at the top of
+// the full-file view, zM closes every fold and zR opens them all
+func (m *Model) foldKey(key string) (tea.Cmd, bool) {
+	if key != "a" && key != "M" && key != "R" {
+		return nil, false
+	}
+	if !m.singleFileMode || m.displayMode != displayFull {
+		return nil, false
+	}
+	var ok bool
+	switch key {
+	case "a":
+		ok = m.diffView.ToggleFold()
+	case "M":
+		ok = m.diffView.SetAllFolds(true)
+	case "R":
+		ok = m.diffView.SetAllFolds(false)
+	}
+	if !ok {
+		return m.setStatus("No fold here"), true
+	}
+	return nil, true
+}
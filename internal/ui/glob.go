@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// promptGlob asks for a pathspec glob that the commit and file lists are limited to
+func (m *Model) promptGlob() tea.Cmd {
+	m.textInput.SetValue(m.gitService.Pathspec())
+	m.textInput.Placeholder = "*.go or internal/**"
+	m.textInput.Focus()
+	m.textInputMode = "glob"
+	return textinput.Blink
+}
+
+// setGlob applies (or, when empty, clears) the pathspec glob and reloads the current view
+func (m *Model) setGlob(glob string) tea.Cmd {
+	m.gitService.SetPathspec(glob)
+	m.preview = nil
+	m.commitIndex = 0
+	m.commitList.SetTitle(m.commitListTitle())
+	switch m.repoView {
+	case viewStashes:
+		return m.loadStashes
+	case viewReflog:
+		return m.loadHeadReflog
+	case viewRefs:
+		return m.loadRefs
+	case viewStaged, viewWorkingCopy:
+		return m.loadFilesForCurrentCommit
+	case viewPR:
+		return m.loadPRView(m.prView.Base + "..." + m.prView.Branch)
+	}
+	return m.loadInitialData
+}
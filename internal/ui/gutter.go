@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// GutterMode selects which line numbers the diff gutter shows and where
+type GutterMode int
+
+const (
+	GutterBoth    GutterMode = iota // Old and new numbers before the content (default)
+	GutterNewOnly                   // Only new-file numbers
+	GutterOldOnly                   // Only old-file numbers
+	GutterRight                     // Old and new numbers after the content
+	GutterNone                      // No line numbers, just the content
+)
+
+// gutterNumWidth is the width of one line number column
+const gutterNumWidth = 4
+
+// parseGutterMode maps a configured gutter name to a GutterMode, defaulting to both
+func parseGutterMode(name string) GutterMode {
+	switch name {
+	case "new-only":
+		return GutterNewOnly
+	case "old-only":
+		return GutterOldOnly
+	case "right":
+		return GutterRight
+	case "none":
+		return GutterNone
+	default:
+		return GutterBoth
+	}
+}
+
+// gutterNum formats a line number column, colored with an SGR code when color is set
+func gutterNum(n int, color string) string {
+	if color == "" {
+		return fmt.Sprintf("%*d", gutterNumWidth, n)
+	}
+	return fmt.Sprintf("\x1b[%sm%*d\x1b[0m", color, gutterNumWidth, n)
+}
+
+// gutterBlank is an empty line number column
+var gutterBlank = strings.Repeat(" ", gutterNumWidth)
+
+// gutterAbsent marks the column of the side a line doesn't exist in (old for an added
+// line, new for a removed one)
+var gutterAbsent = fmt.Sprintf("\x1b[2m%*s\x1b[0m", gutterNumWidth, "·")
+
+// header labels the number columns, laid out like the lines they sit above
+func (g GutterMode) header(width int) string {
+	if g == GutterNone {
+		return ""
+	}
+	label := func(s string) string { return fmt.Sprintf("%*s", gutterNumWidth, s) }
+	return "\x1b[2m" + strings.TrimRight(g.render(label("old"), label("new"), "", width), " ") + "\x1b[0m"
+}
+
+// render joins the old/new number columns with a line's content. width is only
+// used by GutterRight, which pads (or truncates) content so the numbers line up.
+func (g GutterMode) render(oldNum, newNum, content string, width int) string {
+	switch g {
+	case GutterNone:
+		return content
+	case GutterNewOnly:
+		return newNum + " │ " + content
+	case GutterOldOnly:
+		return oldNum + " │ " + content
+	case GutterRight:
+		avail := width - (2*gutterNumWidth + 4) // " │ " + space between numbers
+		if avail < 1 {
+			avail = 1
+		}
+		// Tabs have no fixed width, so expand them to keep the numbers aligned
+		content = ansi.Truncate(strings.ReplaceAll(content, "\t", "    "), avail, "…")
+		pad := strings.Repeat(" ", max(avail-ansi.StringWidth(content), 0))
+		return content + pad + " │ " + oldNum + " " + newNum
+	default:
+		return oldNum + " " + newNum + " │ " + content
+	}
+}
+
+// contentWidth is the width left for a line's content next to the gutter
+func (g GutterMode) contentWidth(width int) int {
+	switch g {
+	case GutterNone:
+		return width
+	case GutterNewOnly, GutterOldOnly:
+		return width - gutterNumWidth - 3
+	default:
+		return width - 2*gutterNumWidth - 4
+	}
+}
@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type headReflogLoadedMsg struct {
+	entries []git.Commit
+	err     error
+}
+
+// toggleHeadReflog switches the commit list between recent commits and HEAD's reflog,
+// where each entry lists the files that step changed
+func (m *Model) toggleHeadReflog() tea.Cmd {
+	m.preview = nil
+	m.commitIndex = 0
+	if m.repoView == viewReflog {
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadInitialData
+	}
+	m.repoView = viewReflog
+	return m.loadHeadReflog
+}
+
+func (m *Model) loadHeadReflog() tea.Msg {
+	entries, err := m.gitService.GetHeadReflog(100)
+	return headReflogLoadedMsg{entries: entries, err: err}
+}
+
+func (m *Model) applyHeadReflog(msg headReflogLoadedMsg) tea.Cmd {
+	if msg.err != nil || len(msg.entries) == 0 {
+		// Fall back to the commit list, which may have been replaced by another view
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		status := "No reflog entries"
+		if msg.err != nil {
+			status = "Failed to read the reflog: " + msg.err.Error()
+		}
+		return tea.Batch(m.setStatus(status), m.loadInitialData)
+	}
+	m.commits = msg.entries
+	m.populateCommitList(msg.entries)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(m.commitIndex)
+	return m.loadFilesForCurrentCommit
+}
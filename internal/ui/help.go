@@ -0,0 +1,200 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// The status bar names a few keys, leaving the rest to the key overview
+const (
+	commitModeHelp = "[j/k: nav | space: file mode | t: tree | [/]: commits | /: jump/filter | ?: keys | q: quit]"
+	fileModeHelp   = "[c: view | [/]: history | /: jump | b: blame split | ?: keys | q: back]"
+	treeHelp       = "[j/k: nav | enter: open | /: search | h/l: collapse/expand | ?: keys | t/esc: close]"
+	keyHelpHelp    = "[j/k: scroll | d/u: half page | esc/?: close]"
+)
+
+// keyBinding is a line of the key overview: the keys and what they do
+type keyBinding struct {
+	keys, action string
+}
+
+var commitModeKeys = []keyBinding{
+	{"1/2/3", "focus"},
+	{"j/k", "nav"},
+	{"space", "file mode"},
+	{"t", "tree"},
+	{"p", "pin file"},
+	{"*", "glob"},
+	{"@", "owner"},
+	{"%", "status filter"},
+	{"D", "diff files"},
+	{"[/]", "commits"},
+	{"/", "jump/filter"},
+	{"n/N", "hunks"},
+	{"}/{", "conflicts"},
+	{"P/R", "pick/revert preview"},
+	{"=", "diff vs ref"},
+	{"&", "merge resolution"},
+	{"S", "stashes"},
+	{"r", "reflog"},
+	{"J", "refs"},
+	{"K", "commits from commit's ref"},
+	{"ctrl+k", "copy commit's ref"},
+	{"a", "all branches"},
+	{"F", "type filter"},
+	{"B", "PR view"},
+	{"ctrl+f", "fetch & review"},
+	{"w", "working copy"},
+	{"+/-/!", "stage/unstage/discard file"},
+	{"i", "staged"},
+	{"U", "unstage hunk"},
+	{"O", "line origin"},
+	{"z", "info"},
+	{"zz/zt/zb", "center/top/bottom"},
+	{"#", "line numbers"},
+	{"e", "long lines"},
+	{"E", "line endings"},
+	{"H", "commit counts"},
+	{"x", "delta"},
+	{"T", "textconv"},
+	{"ctrl+w", "wrap"},
+	{"ctrl+t", "group by date"},
+	{"$", "diff stats"},
+	{"|", "resize"},
+	{"ctrl+o/n", "back/fwd"},
+	{"L", "lock"},
+	{"ctrl+l", "reload diff"},
+	{"Y", "suggest"},
+	{"y", "copy line ref"},
+	{"ctrl+g", "copy git show"},
+	{"o", "pager"},
+	{"`", "git log"},
+	{"ctrl+s", "save view"},
+	{"q", "quit"},
+}
+
+var fileModeKeys = []keyBinding{
+	{"1/2/3", "focus"},
+	{"/", "jump"},
+	{"c", "view"},
+	{"za/zM/zR", "folds"},
+	{"r", "reflog"},
+	{"s", "search"},
+	{"S", "stashes"},
+	{"m/M", "mark/compare"},
+	{"ctrl+b", "vs tag"},
+	{"b", "blame split"},
+	{"V", "blame lines"},
+	{"ctrl+a", "author lines"},
+	{"D", "diff files"},
+	{"d/u", "scroll"},
+	{"n/N", "hunks"},
+	{"}/{", "conflicts"},
+	{"[/]", "history"},
+	{"O", "line origin"},
+	{"z", "info"},
+	{"zz/zt/zb", "center/top/bottom"},
+	{"#", "line numbers"},
+	{"e", "long lines"},
+	{"E", "line endings"},
+	{"x", "delta"},
+	{"T", "textconv"},
+	{"ctrl+w", "wrap"},
+	{"ctrl+t", "group by date"},
+	{"$", "diff stats"},
+	{"|", "resize"},
+	{"ctrl+o/n", "back/fwd"},
+	{"L", "lock"},
+	{"ctrl+l", "reload diff"},
+	{"Y", "suggest"},
+	{"y", "copy line ref"},
+	{"ctrl+y", "copy file"},
+	{"ctrl+g", "copy git show"},
+	{"o", "pager"},
+	{"`", "git log"},
+	{"ctrl+s", "save view"},
+	{"esc", "cancel load"},
+	{"q", "back"},
+}
+
+var treeKeys = []keyBinding{
+	{"j/k", "nav"},
+	{"enter", "open"},
+	{"/", "search"},
+	{"h/l", "collapse/expand"},
+	{"+/-", "expand depth"},
+	{"^", "HEAD/commit tree"},
+	{"t/esc", "close"},
+	{"q", "quit"},
+}
+
+// modeKeys returns the keys of the current mode
+func (m *Model) modeKeys() []keyBinding {
+	switch {
+	case m.singleFileMode:
+		return fileModeKeys
+	case m.showFileTree:
+		return treeKeys
+	}
+	return commitModeKeys
+}
+
+// OpenKeyHelp shows every key of the current mode, which the status bar has no room for
+func (m *Model) OpenKeyHelp() {
+	vp := viewport.New(max(m.width-2, 1), max(m.height-3, 1))
+	vp.SetContent(renderKeys(m.modeKeys()))
+	m.keyHelp = &vp
+}
+
+// resizeKeyHelp fits the key overview to the window
+func (m *Model) resizeKeyHelp() {
+	m.keyHelp.Width = max(m.width-2, 1)
+	m.keyHelp.Height = max(m.height-3, 1)
+}
+
+// renderKeys lists keys one per line, their actions lined up in a column
+func renderKeys(keys []keyBinding) string {
+	width := 0
+	for _, k := range keys {
+		width = max(width, len(k.keys))
+	}
+	var b strings.Builder
+	for _, k := range keys {
+		fmt.Fprintf(&b, "%s  %s\n", TitleStyle.Render(fmt.Sprintf("%-*s", width, k.keys)), k.action)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// answerKeyHelp scrolls the key overview or closes it
+func (m *Model) answerKeyHelp(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "?", "q":
+		m.keyHelp = nil
+		return nil
+	case "ctrl+c":
+		return m.quit()
+	case "d":
+		m.keyHelp.HalfViewDown()
+		return nil
+	case "u":
+		m.keyHelp.HalfViewUp()
+		return nil
+	}
+	var cmd tea.Cmd
+	*m.keyHelp, cmd = m.keyHelp.Update(msg)
+	return cmd
+}
+
+// keyHelpView draws the key overview over the whole screen
+func (m Model) keyHelpView() string {
+	box := lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("2")).
+		Render(m.keyHelp.View())
+	help := ModeBadgeCommits.Render("KEYS") + " " + HelpStyle.Render(keyHelpHelp)
+	return lipgloss.JoinVertical(lipgloss.Left, box, help)
+}
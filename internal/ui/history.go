@@ -0,0 +1,110 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// maxNavHistory bounds the back/forward stacks
+const maxNavHistory = 50
+
+// navState is a snapshot of where the user is, recorded before major navigation
+type navState struct {
+	singleFileMode  bool
+	currentFile     string
+	commitIndex     int
+	fileCommitIndex int
+	displayMode     displayMode
+	sourceMode      sourceMode
+	reflogIndex     int
+	sourceIndex     int
+	pickaxeTerm     string
+}
+
+func (m *Model) snapshotNavState() navState {
+	return navState{
+		singleFileMode:  m.singleFileMode,
+		currentFile:     m.currentFile,
+		commitIndex:     m.commitIndex,
+		fileCommitIndex: m.fileCommitIndex,
+		displayMode:     m.displayMode,
+		sourceMode:      m.sourceMode,
+		reflogIndex:     m.reflogIndex,
+		sourceIndex:     m.sourceIndex,
+		pickaxeTerm:     m.pickaxeTerm,
+	}
+}
+
+// pushHistory records the current view before navigating away from it.
+// A new jump invalidates the forward stack, like vim's jumplist.
+func (m *Model) pushHistory() {
+	m.navBack = pushNavState(m.navBack, m.snapshotNavState())
+	m.navForward = nil
+}
+
+func pushNavState(stack []navState, s navState) []navState {
+	if n := len(stack); n > 0 && stack[n-1] == s {
+		return stack
+	}
+	stack = append(stack, s)
+	if len(stack) > maxNavHistory {
+		stack = stack[len(stack)-maxNavHistory:]
+	}
+	return stack
+}
+
+// navigateBack restores the previous view, saving the current one for navigateForward
+func (m *Model) navigateBack() tea.Cmd {
+	if len(m.navBack) == 0 {
+		return m.setStatus("No earlier location")
+	}
+	prev := m.navBack[len(m.navBack)-1]
+	m.navBack = m.navBack[:len(m.navBack)-1]
+	m.navForward = pushNavState(m.navForward, m.snapshotNavState())
+	return m.restoreNavState(prev)
+}
+
+// navigateForward re-applies a view undone by navigateBack
+func (m *Model) navigateForward() tea.Cmd {
+	if len(m.navForward) == 0 {
+		return m.setStatus("No later location")
+	}
+	next := m.navForward[len(m.navForward)-1]
+	m.navForward = m.navForward[:len(m.navForward)-1]
+	m.navBack = pushNavState(m.navBack, m.snapshotNavState())
+	return m.restoreNavState(next)
+}
+
+func (m *Model) restoreNavState(s navState) tea.Cmd {
+	if m.showFileTree {
+		m.showFileTree = false
+		m.updateLayout()
+	}
+	m.preview = nil
+
+	if !s.singleFileMode {
+		if m.singleFileMode {
+			m.exitSingleFileMode()
+		}
+		m.commitIndex = s.commitIndex
+		m.commitList.SelectIndex(m.commitIndex)
+		m.restoreFile = s.currentFile
+		return m.loadFilesForCurrentCommit
+	}
+
+	m.currentFile = s.currentFile
+	m.enterSingleFileMode()
+	m.displayMode = s.displayMode
+	m.diffView.SetMode(true, int(m.displayMode))
+	m.fileCommitIndex = s.fileCommitIndex
+	m.sourceMode = s.sourceMode
+	m.reflogIndex = s.reflogIndex
+	m.sourceIndex = s.sourceIndex
+	m.pickaxeTerm = s.pickaxeTerm
+	m.updateSourceIndicator()
+
+	switch s.sourceMode {
+	case sourceReflog:
+		return tea.Sequence(m.loadFileCommits, m.loadReflog)
+	case sourcePickaxe:
+		return tea.Sequence(m.loadFileCommits, m.loadPickaxeCommits)
+	}
+	return m.loadFileCommits
+}
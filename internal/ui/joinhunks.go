@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// hunkRangeRegex captures the ranges of a hunk header ("-10,5 +12,7"), a missing
+// count meaning one line
+var hunkRangeRegex = regexp.MustCompile(`^@@\s+(-(\d+)(?:,(\d+))?\s+\+(\d+)(?:,(\d+))?)\s+@@`)
+
+// hunkRange is the old and new lines a hunk covers
+type hunkRange struct {
+	oldStart, oldCount int
+	newStart, newCount int
+}
+
+// parseHunkRange reads a hunk header's ranges and returns them with their text
+func parseHunkRange(stripped string) (hunkRange, string, bool) {
+	m := hunkRangeRegex.FindStringSubmatch(stripped)
+	if m == nil {
+		return hunkRange{}, "", false
+	}
+	count := func(s string) int {
+		if s == "" {
+			return 1
+		}
+		n, _ := strconv.Atoi(s)
+		return n
+	}
+	oldStart, _ := strconv.Atoi(m[2])
+	newStart, _ := strconv.Atoi(m[4])
+	return hunkRange{oldStart, count(m[3]), newStart, count(m[5])}, m[1], true
+}
+
+func (r hunkRange) String() string {
+	return fmt.Sprintf("-%d,%d +%d,%d", r.oldStart, r.oldCount, r.newStart, r.newCount)
+}
+
+// joinAdjacentHunks shows hunks whose context overlaps or meets as one continuous block:
+// the later header goes, along with the context lines both hunks show, and the earlier
+// header widens to cover both so line numbers carry on across the join. Git never emits
+// such hunks (the service joins nearby ones with --inter-hunk-context), but patch files
+// that were hand-edited or concatenated can.
+func joinAdjacentHunks(content string) string {
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+	header := -1 // Index in result of the current hunk's header
+	var cur hunkRange
+	var curText string
+	for i := 0; i < len(lines); i++ {
+		stripped := stripANSI(lines[i])
+		r, text, ok := parseHunkRange(stripped)
+		if !ok {
+			if strings.HasPrefix(stripped, "diff ") {
+				header = -1 // Hunks of the next file never join this one's
+			}
+			result = append(result, lines[i])
+			continue
+		}
+		overlap := cur.oldStart + cur.oldCount - r.oldStart
+		if header >= 0 && overlap >= 0 && overlap == cur.newStart+cur.newCount-r.newStart &&
+			overlap <= min(r.oldCount, r.newCount) && leadingContext(lines[i+1:], overlap) {
+			joined := hunkRange{
+				oldStart: cur.oldStart, oldCount: r.oldStart + r.oldCount - cur.oldStart,
+				newStart: cur.newStart, newCount: r.newStart + r.newCount - cur.newStart,
+			}
+			// The ranges are one run of text even when git colors the header
+			result[header] = strings.Replace(result[header], curText, joined.String(), 1)
+			cur, curText = joined, joined.String()
+			i += overlap
+			continue
+		}
+		header = len(result)
+		cur, curText = r, text
+		result = append(result, lines[i])
+	}
+	return strings.Join(result, "\n")
+}
+
+// leadingContext reports whether lines starts with n unchanged lines
+func leadingContext(lines []string, n int) bool {
+	if len(lines) < n {
+		return false
+	}
+	for _, line := range lines[:n] {
+		stripped := stripANSI(line)
+		if stripped != "" && stripped[0] != ' ' {
+			return false
+		}
+	}
+	return true
+}
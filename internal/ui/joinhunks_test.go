@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJoinAdjacentHunks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []string
+		want    []string
+	}{
+		{
+			"overlapping context",
+			[]string{
+				"@@ -1,4 +1,4 @@ func a",
+				" a",
+				"-b",
+				"+B",
+				" c",
+				" d",
+				"@@ -3,4 +3,4 @@",
+				" c",
+				" d",
+				"-e",
+				"+E",
+				" f",
+			},
+			[]string{
+				"@@ -1,6 +1,6 @@ func a",
+				" a",
+				"-b",
+				"+B",
+				" c",
+				" d",
+				"-e",
+				"+E",
+				" f",
+			},
+		},
+		{
+			"abutting hunks",
+			[]string{
+				"@@ -1,2 +1,2 @@",
+				"-a",
+				"+A",
+				" b",
+				"@@ -3,2 +3,2 @@",
+				" c",
+				"-d",
+				"+D",
+			},
+			[]string{
+				"@@ -1,4 +1,4 @@",
+				"-a",
+				"+A",
+				" b",
+				" c",
+				"-d",
+				"+D",
+			},
+		},
+		{
+			"lines between the hunks",
+			[]string{
+				"@@ -1,2 +1,2 @@",
+				"-a",
+				"+A",
+				" b",
+				"@@ -10,2 +10,2 @@",
+				" j",
+				"-k",
+				"+K",
+			},
+			nil,
+		},
+		{
+			"hunks of different files",
+			[]string{
+				"diff --git a/x b/x",
+				"@@ -1,2 +1,2 @@",
+				"-a",
+				"+A",
+				" b",
+				"diff --git a/y b/y",
+				"@@ -3,2 +3,2 @@",
+				" c",
+				"-d",
+				"+D",
+			},
+			nil,
+		},
+		{
+			"shared lines that are not context",
+			[]string{
+				"@@ -1,2 +1,2 @@",
+				" a",
+				"-b",
+				"+B",
+				"@@ -2,2 +2,2 @@",
+				"-b",
+				"+B",
+				" c",
+			},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := strings.Join(tt.content, "\n")
+			want := content
+			if tt.want != nil {
+				want = strings.Join(tt.want, "\n")
+			}
+			if got := joinAdjacentHunks(content); got != want {
+				t.Errorf("joinAdjacentHunks:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
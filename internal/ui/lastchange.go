@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type lastChangeMsg struct {
+	gen    int
+	file   string
+	change *git.LastChange
+}
+
+// lastChangeText describes who last touched a file, e.g. "abc1234 · Ana · 2 days ago"
+func lastChangeText(c *git.LastChange) string {
+	if c == nil {
+		return "Not committed yet"
+	}
+	return c.Hash + " · " + c.Author + " · " + c.When
+}
+
+// updateLastChange shows who last touched the current file below the file list,
+// loading it when the file isn't cached yet
+func (m *Model) updateLastChange() tea.Cmd {
+	if m.currentFile == m.lastChangeFile {
+		return nil
+	}
+	m.lastChangeFile = m.currentFile
+	if m.currentFile == "" {
+		m.sidebar.SetFooter("")
+		return nil
+	}
+	if text, ok := m.lastChanges[m.currentFile]; ok {
+		m.sidebar.SetFooter(text)
+		return nil
+	}
+	m.sidebar.SetFooter("Last change: …")
+	file := m.currentFile
+	gen := m.lastChangesGen
+	return func() tea.Msg {
+		change, err := m.gitService.GetLastChange(file)
+		if err != nil {
+			return nil
+		}
+		return lastChangeMsg{gen: gen, file: file, change: change}
+	}
+}
+
+// resetLastChanges drops the cached last changes, as when HEAD moves and they may be stale
+func (m *Model) resetLastChanges() {
+	m.lastChanges = map[string]string{}
+	m.lastChangesGen++
+	m.lastChangeFile = ""
+}
+
+// applyLastChange caches a file's last change, showing it if the file is still current
+func (m *Model) applyLastChange(msg lastChangeMsg) {
+	if msg.gen != m.lastChangesGen {
+		return
+	}
+	text := lastChangeText(msg.change)
+	m.lastChanges[msg.file] = text
+	if msg.file == m.currentFile {
+		m.sidebar.SetFooter(text)
+	}
+}
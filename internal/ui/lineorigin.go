@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type lineOriginMsg struct {
+	hash string
+	line int
+	err  error
+}
+
+// findLineOrigin blames the line at the top of the diff view to find the commit that introduced it
+func (m *Model) findLineOrigin() tea.Cmd {
+	if m.currentFile == "" || m.showFileTree {
+		return nil
+	}
+	var hash string
+	if m.singleFileMode {
+		h, ok := m.currentCommitForSource()
+		if !ok {
+			return nil
+		}
+		hash = h
+	} else if m.commitIndex < len(m.commits) {
+		hash = m.commits[m.commitIndex].Hash
+	}
+	if hash == "" || hash == stagedEntry.Hash || hash == workingCopyEntry.Hash || hash == prAllChanges {
+		return m.setStatus("Line origin needs a single commit")
+	}
+	line, ok := m.diffView.TopFileLine()
+	if !ok {
+		return m.setStatus("No file line at the top of the view")
+	}
+
+	file := m.currentFile
+	return func() tea.Msg {
+		origin, err := m.gitService.GetLineOrigin(file, hash, line)
+		return lineOriginMsg{hash: origin, line: line, err: err}
+	}
+}
+
+// applyLineOrigin selects the commit that introduced the line, when it is in the current list
+func (m *Model) applyLineOrigin(msg lineOriginMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Blame failed: %v", msg.err))
+	}
+	short := msg.hash
+	if len(short) > 7 {
+		short = short[:7]
+	}
+
+	if m.singleFileMode {
+		idx := indexOfCommit(m.fileCommits, msg.hash)
+		if idx < 0 {
+			return m.setStatus(fmt.Sprintf("Line %d introduced in %s (not in this file's history)", msg.line, short))
+		}
+		m.pushHistory()
+		if m.sourceMode != sourceCommits {
+			m.switchSource(sourceCommits)
+			m.updateSourceIndicator()
+		}
+		m.fileCommitIndex = idx
+		m.pendingOffset = 0
+		m.commitList.SelectIndex(idx)
+		m.updateSingleFileModeDisplay()
+		return tea.Batch(m.setStatus(fmt.Sprintf("Line %d introduced in %s", msg.line, short)), m.loadContentForCurrentSource())
+	}
+
+	idx := indexOfCommit(m.commits, msg.hash)
+	if idx < 0 {
+		return m.setStatus(fmt.Sprintf("Line %d introduced in %s (not in this list)", msg.line, short))
+	}
+	m.pushHistory()
+	m.commitIndex = idx
+	m.commitList.SelectIndex(idx)
+	m.restoreFile = m.currentFile
+	return tea.Batch(m.setStatus(fmt.Sprintf("Line %d introduced in %s", msg.line, short)), m.loadFilesForCurrentCommit)
+}
+
+// indexOfCommit finds a commit by hash, allowing either side to be abbreviated
+func indexOfCommit(commits []git.Commit, hash string) int {
+	for i, c := range commits {
+		if c.Hash != "" && (strings.HasPrefix(hash, c.Hash) || strings.HasPrefix(c.Hash, hash)) {
+			return i
+		}
+	}
+	return -1
+}
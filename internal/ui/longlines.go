@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Lines longer than longLineLimit bytes (minified or generated files) skip word-level
+// highlighting and, unless expanded, keep only their ends around a marker
+const (
+	longLineLimit = 2000
+	longLineHead  = 120
+	longLineTail  = 40
+)
+
+// elideLongLines shortens every line over longLineLimit, keeping one output line per
+// input line so line positions are unchanged
+func elideLongLines(content string) string {
+	if len(content) <= longLineLimit {
+		return content
+	}
+	lines := strings.Split(content, "\n")
+	changed := false
+	for i, line := range lines {
+		if len(line) > longLineLimit {
+			lines[i] = elideLine(line)
+			changed = true
+		}
+	}
+	if !changed {
+		return content
+	}
+	return strings.Join(lines, "\n")
+}
+
+// elideLine replaces the middle of a line with "…[N chars]…". Styling is dropped, as an
+// escape sequence may be cut; the diff renderer colors +/- lines from the text alone.
+func elideLine(line string) string {
+	runes := []rune(stripANSI(line))
+	if len(runes) <= longLineHead+longLineTail {
+		return string(runes)
+	}
+	hidden := len(runes) - longLineHead - longLineTail
+	return fmt.Sprintf("%s\x1b[2m…[%d chars]…\x1b[0m%s",
+		string(runes[:longLineHead]), hidden, string(runes[len(runes)-longLineTail:]))
+}
+
+// ToggleLongLines shows long lines in full or shortened again, reporting whether they are full
+func (d *DiffView) ToggleLongLines() bool {
+	d.expandLongLines = !d.expandLongLines
+	d.updateContent()
+	return d.expandLongLines
+}
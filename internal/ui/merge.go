@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// combinedHunkRegex matches the hunk header of a combined diff, with one @ more than
+// the merge has parents: "@@@ -1,4 -1,4 +1,5 @@@"
+var combinedHunkRegex = regexp.MustCompile(`^(@{3,})(?: -\d+(?:,\d+)?)+ \+(\d+)(?:,\d+)? @{3,}`)
+
+// mergeResolutionBanner explains the columns of a merge resolution diff
+const mergeResolutionBanner = "How the merge resolved this file — a column per parent; ++ lines are in neither parent"
+
+// isCombinedDiff reports whether content is a combined diff of a merge (git show --cc)
+func isCombinedDiff(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		if stripped := stripANSI(line); strings.HasPrefix(stripped, "@@") {
+			return combinedHunkRegex.MatchString(stripped)
+		}
+	}
+	return false
+}
+
+// renderCombinedDiff adds the gutter to a combined diff, numbering the lines of the
+// merge result, and colors each line by where it came from: removed from a parent,
+// added from one parent, or in neither parent (the merge's own resolution)
+func renderCombinedDiff(content string, gutter GutterMode, colors diffColorScheme, width int) (string, []int) {
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+	var hunkPositions []int
+	parents, newLine := 0, 0
+
+	for _, line := range lines {
+		stripped := stripANSI(line)
+		if m := combinedHunkRegex.FindStringSubmatch(stripped); m != nil {
+			parents = len(m[1]) - 1
+			newLine, _ = strconv.Atoi(m[2])
+			hunkPositions = append(hunkPositions, len(result))
+			result = append(result, gutter.render(gutterBlank, gutterBlank, "\x1b[36m"+stripped+"\x1b[0m", width))
+			continue
+		}
+		if parents == 0 || len(stripped) < parents {
+			result = append(result, gutter.render(gutterBlank, gutterBlank, line, width))
+			continue
+		}
+
+		cols := stripped[:parents]
+		switch {
+		case strings.Contains(cols, "-"):
+			result = append(result, gutter.render(gutterBlank, gutterAbsent, "\x1b["+colors.removed+"m"+stripped+"\x1b[0m", width))
+			continue
+		case strings.Count(cols, "+") == parents:
+			line = "\x1b[1;33m" + stripped + "\x1b[0m"
+		case strings.Contains(cols, "+"):
+			line = "\x1b[" + colors.added + "m" + stripped + "\x1b[0m"
+		case colors.context != "":
+			line = "\x1b[" + colors.context + "m" + stripped + "\x1b[0m"
+		default:
+			line = stripped
+		}
+		result = append(result, gutter.render(gutterBlank, gutterNum(newLine, ""), line, width))
+		newLine++
+	}
+	return strings.Join(result, "\n"), hunkPositions
+}
+
+// toggleMergeResolution switches the file list of merge commits to the files the merge
+// resolved, each shown as a combined diff against the parents
+func (m *Model) toggleMergeResolution() tea.Cmd {
+	m.mergeResolution = !m.mergeResolution
+	state := "off"
+	if m.mergeResolution {
+		state = "on"
+	}
+	return tea.Batch(m.setStatus("Merge resolution "+state), m.loadFilesForCurrentCommit)
+}
+
+// loadMergeFiles lists the files a merge resolved, reporting false for other commits
+func (m *Model) loadMergeFiles(commit git.Commit) ([]FileItem, bool) {
+	if commit.Parents < 2 {
+		return nil, false
+	}
+	files, err := m.gitService.GetMergeFiles(commit.Hash)
+	if err != nil {
+		return nil, false
+	}
+	items := make([]FileItem, 0, len(files))
+	for _, f := range files {
+		items = append(items, FileItem{Path: f.Path, Status: f.Status})
+	}
+	return items, true
+}
+
+// loadMergeResolution shows how a merge resolved the current file
+func (m *Model) loadMergeResolution(hash string) tea.Msg {
+	diff, err := m.gitService.GetMergeResolution(hash, m.currentFile)
+	if err != nil {
+		return ErrorMsg{Err: err}
+	}
+	if diff == "" {
+		return diffLoadedMsg{content: fmt.Sprintf("%s matches one of the parents", m.currentFile)}
+	}
+	return diffLoadedMsg{content: diff, banner: mergeResolutionBanner}
+}
@@ -2,12 +2,18 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
+	"time"
+	"var/internal/config"
 	"var/internal/git"
 
+	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 type focus int
@@ -28,6 +34,34 @@ const (
 	displayBlame                     // Blame annotations
 )
 
+// parseDisplayMode maps a configured mode name to a displayMode
+func parseDisplayMode(name string) (displayMode, bool) {
+	switch strings.ToLower(name) {
+	case "diff":
+		return displayDiff, true
+	case "ctx", "context":
+		return displayContext, true
+	case "full":
+		return displayFull, true
+	case "blame":
+		return displayBlame, true
+	}
+	return displayDiff, false
+}
+
+// repoView selects what the commit list shows in commits mode
+type repoView int
+
+const (
+	viewCommits     repoView = iota // Recent commits (default)
+	viewStashes                     // Stash entries
+	viewStaged                      // Changes staged in the index
+	viewPR                          // A branch's commits and diff against its base
+	viewWorkingCopy                 // Everything changed since HEAD, staged or not
+	viewReflog                      // HEAD's reflog, each entry showing what that step changed
+	viewRefs                        // Branches and tags, to show the commits from one of them
+)
+
 type sourceMode int
 
 const (
@@ -43,15 +77,34 @@ type Model struct {
 	diffView   DiffView
 	fileTree   FileTree
 	gitService *git.Service
+	config     config.Config
 
-	focus        focus
-	showFileTree bool
-	width        int
-	height       int
+	focus         focus
+	showFileTree  bool
+	wrapSubjects  bool // Commit subjects wrap onto a second line instead of truncating
+	treePreviewID int  // Debounce token for tree hover previews
+	width         int
+	height        int
+
+	// Commit the tree lists, resolved from HEAD when it loads, and previews of its files
+	// (filled ahead by prefetches when a directory opens)
+	treeHead  string
+	treeCache *treeCache
+
+	// The tree lists the selected commit's files instead of HEAD's (^)
+	treeAtCommit bool
 
 	// Commit navigation (repo-wide)
-	commits     []git.Commit // All recent commits
-	commitIndex int          // -1 for working copy, 0+ for commits
+	commits      []git.Commit  // Recent commits shown in the list
+	allCommits   []git.Commit  // Recent commits before the type filter
+	typeFilter   string        // Conventional-commit type to show, empty for all
+	commitIndex  int           // -1 for working copy, 0+ for commits
+	repoView     repoView      // What the commit list shows
+	stashBase    git.StashBase // Comparison base in the stash view
+	lastHead     string        // HEAD at the last poll, to detect commits made elsewhere
+	headLabel    string        // Current branch, or "(detached at abc1234)"
+	prView       *git.PRView   // Branch under review in the PR view
+	prDivergence string        // The reviewed branch's commits ahead of/behind its base, e.g. "↑3 ↓1"
 
 	// Current file selection
 	currentFile string
@@ -62,29 +115,148 @@ type Model struct {
 	fileCommitIndex int          // -1 for working copy, 0+ for file commits
 	displayMode     displayMode  // Current display format
 	sourceMode      sourceMode   // Current commit source
+	markedCommit    string       // Commit marked for side-by-side comparison
+	blameSplit      bool         // Show blame beside the file instead of the display mode
+	fileStashes     bool         // Interleave the stashes that touched the file into its history
 
 	// Source-specific state
 	reflogEntries []git.Commit
 	reflogIndex   int
+	reflogFile    string       // File the reflog entries were loaded for
 	sourceCommits []git.Commit // Commits from pickaxe
 	sourceIndex   int
 	pickaxeTerm   string // Active search term for pickaxe
 
+	// Diff scroll offset remembered per source, restored when switching back
+	sourceOffsets map[sourceMode]int
+	pendingOffset int // Offset to apply once the next content loads, -1 for none
+
 	// Text input for pickaxe
 	textInput     textinput.Model
-	textInputMode string // "pickaxe" or ""
+	textInputMode string // "pickaxe", "pr", "fetch", "glob", "compare", "owner", "refdiff", "author" or ""
+
+	// Cherry-pick / revert preview of the selected commit (nil when inactive)
+	preview *git.PickPreview
+
+	// Comparison of the selected commit with a typed ref (nil when inactive)
+	refDiff *refDiff
+
+	// List the files merge commits resolved, as combined diffs against the parents
+	mergeResolution bool
+
+	// Preview lock: list selection changes wait for enter before loading
+	previewLocked bool
+	pendingLoad   tea.Cmd
+
+	// Navigation history (ctrl+o / ctrl+n)
+	navBack     []navState
+	navForward  []navState
+	restoreFile string // File to reselect once the commit's files load
+	pinnedFile  string // File kept selected while moving between commits
+
+	// Branches and tags listed while the commit list shows refs, and the ref the commits
+	// are listed from (empty for HEAD)
+	refs     []git.Ref
+	logStart string
+
+	// Which of the selected commit's refs K and ctrl+k use next, so repeated presses
+	// cycle through them
+	commitRefHash string
+	commitRefTurn int
+
+	// Whether the commit list shows every branch's commits instead of HEAD's, and whether
+	// its next page is there to load
+	allRefs     bool
+	moreCommits bool
+	loadingMore bool
+
+	// Status the file list is limited to ("A", "D", "M" or "R"; empty for every file)
+	statusFilter string
+
+	// Who last touched each file, shown below the file list for the current one, by file;
+	// lastChangesGen discards loads from before HEAD moved
+	lastChanges    map[string]string
+	lastChangesGen int
+	lastChangeFile string
+
+	// Last title set on the terminal, when the windowTitle option is on
+	windowTitle string
+
+	// CODEOWNERS rules (nil without a CODEOWNERS file) and the owner the files are limited to
+	codeOwners  *git.CodeOwners
+	ownerFilter string
+
+	// Commits touching each listed file (nil while hidden, -1 while counting)
+	commitCounts    map[string]int
+	commitCountsGen int
+
+	// Lines each listed commit adds and deletes (nil while hidden, pendingStat while loading)
+	commitStats map[string]git.CommitStat
+	// The commit list's items changed since their stats were last asked for
+	commitStatsDue bool
+
+	// Transient message shown in the help bar
+	statusMsg string
+	statusID  int
+
+	// Slow load in progress, shown with a spinner (nil when idle)
+	operation   *operation
+	operationID int
+	spinner     spinner.Model
+
+	// Quit confirmation after the session staged or unstaged changes
+	dirtiedIndex   bool
+	confirmingQuit bool
+
+	// File waiting on confirmation to discard its working tree changes
+	confirmingDiscard string
+
+	// Share of the width given to the left column, and the ratio to restore while
+	// resize mode adjusts it
+	sidebarRatio float64
+	resizing     bool
+	resizeFrom   float64
+
+	// Set by z until the next key or zTimeout: z, t, b, a, M or R make it a command,
+	// anything else or no key at all toggles the description
+	zPending bool
+	zID      int // Tells the latest z's timeout from earlier ones
+
+	// Full-screen log of the git commands run (nil when closed)
+	commandLog *viewport.Model
+
+	// Full-screen list of the current mode's keys (nil when closed)
+	keyHelp *viewport.Model
 
 	err error
 }
 
-func NewModel(gitService *git.Service) Model {
+func NewModel(gitService *git.Service, cfg config.Config) Model {
 	commitList := NewCommitList(40, 10)
 	commitList.SetFocused(true)
+	commitList.SetGroupByDate(cfg.GroupCommitsByDate)
+	commitList.SetFormat(cfg.CommitFormat)
+	var commitStats map[string]git.CommitStat
+	if cfg.ShowCommitStats {
+		commitStats = map[string]git.CommitStat{}
+		commitList.SetStats(commitStats)
+	}
 
 	sidebar := NewSidebar([]FileItem{}, 40, 10)
+	sidebar.SetTruncateMode(parseTruncateMode(cfg.PathTruncation))
+	sidebar.SetShowOwners(cfg.ShowCodeOwners)
 	sidebar.SetRevision("working copy")
 	diffView := NewDiffView(80, 20)
+	if cfg.DiffRenderer == "delta" && deltaAvailable() {
+		diffView.SetDelta(true)
+	}
+	diffView.SetGutterMode(parseGutterMode(cfg.Gutter))
+	diffView.SetJoinHunks(cfg.JoinNearbyHunks)
+	diffView.SetColors(parseDiffColors(cfg.DiffColors))
+	gitService.SetTextconv(!cfg.DisableTextconv)
+	gitService.SetJoinNearbyHunks(cfg.JoinNearbyHunks)
 	fileTree := NewFileTree(40, 20)
+	fileTree.SetExpandDepth(cfg.TreeExpandDepthOrDefault())
 
 	ti := textinput.New()
 	ti.CharLimit = 128
@@ -94,26 +266,35 @@ func NewModel(gitService *git.Service) Model {
 		sidebar:         sidebar,
 		diffView:        diffView,
 		fileTree:        fileTree,
+		treeCache:       newTreeCache(),
+		lastChanges:     map[string]string{},
+		commitStats:     commitStats,
+		sidebarRatio:    clampSidebarRatio(config.LoadState().SidebarRatio),
 		gitService:      gitService,
+		config:          cfg,
 		focus:           focusCommitList,
 		commitIndex:     0, // Start at latest commit
 		fileCommitIndex: 0,
 		textInput:       ti,
+		spinner:         spinner.New(spinner.WithSpinner(spinner.MiniDot)),
+		sourceOffsets:   make(map[sourceMode]int),
+		pendingOffset:   -1,
 	}
 }
 
 func (m Model) Init() tea.Cmd {
-	return m.loadInitialData
+	return tea.Batch(m.loadStartupData, m.pollHead(), m.loadCodeOwners)
 }
 
 type initialDataMsg struct {
-	commits []git.Commit
-	files   []FileItem
+	commits   []git.Commit
+	files     []FileItem
+	headLabel string
 }
 
 func (m *Model) loadInitialData() tea.Msg {
 	// Load recent commits
-	commits, _ := m.gitService.GetRecentCommits(100)
+	commits, _ := m.gitService.GetCommitsFrom(m.commitLogRev(), commitPageSize)
 
 	// Load files from first commit
 	var items []FileItem
@@ -122,7 +303,7 @@ func (m *Model) loadInitialData() tea.Msg {
 		stats, _ := m.gitService.GetNumstatForCommit(commits[0].Hash)
 		items = make([]FileItem, len(files))
 		for i, f := range files {
-			item := FileItem{Path: f.Path, Status: f.Status}
+			item := FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity}
 			if stats != nil {
 				if s, ok := stats[f.Path]; ok {
 					item.Additions = s.Additions
@@ -134,9 +315,20 @@ func (m *Model) loadInitialData() tea.Msg {
 	}
 
 	return initialDataMsg{
-		commits: commits,
-		files:   items,
+		commits:   commits,
+		files:     items,
+		headLabel: m.currentHeadLabel(),
+	}
+}
+
+// currentHeadLabel describes where HEAD is for the help bar, empty if it can't be read
+func (m *Model) currentHeadLabel() string {
+	head, err := m.gitService.GetHead()
+	if err != nil {
+		return ""
 	}
+	branch, _ := m.gitService.GetHeadBranch()
+	return headLabel(head, branch)
 }
 
 type filesLoadedMsg struct {
@@ -144,11 +336,17 @@ type filesLoadedMsg struct {
 }
 
 type diffLoadedMsg struct {
-	content string
+	content     string
+	banner      string // Notice shown above the content
+	firstChange int    // Full-file content: the first line the commit changed, 0 if unknown
+
+	// Full-file content: the lines to pick out as one author's, nil for none
+	authorLines map[int]bool
 }
 
 type fileCommitsLoadedMsg struct {
-	commits []git.Commit
+	commits  []git.Commit
+	selected string // Hash to keep selected, if it's still listed
 }
 
 type reflogLoadedMsg struct {
@@ -161,14 +359,58 @@ type sourceCommitsLoadedMsg struct {
 }
 
 type treeFilesLoadedMsg struct {
-	paths []string
+	paths  []string
+	head   string
+	sparse bool   // paths are limited to the sparse checkout
+	rev    string // The commit listed instead of HEAD, if any
+}
+
+type statusClearMsg struct {
+	id int
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+	next := model.(Model)
+	// A long diff shows its first screens at once and finishes rendering in the background
+	if render := next.diffView.takePendingRender(); render != nil {
+		cmd = tea.Batch(cmd, render)
+	}
+	if title := next.updateWindowTitle(); title != nil {
+		cmd = tea.Batch(cmd, title)
+	}
+	if change := next.updateLastChange(); change != nil {
+		cmd = tea.Batch(cmd, change)
+	}
+	// Commits newly listed, by a page load or a switch of view, get their stats
+	if stats := next.takeCommitStats(); stats != nil {
+		cmd = tea.Batch(cmd, stats)
+	}
+	return next, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		// A pending quit prompt takes the next key
+		if m.confirmingQuit {
+			return m, m.answerQuitPrompt(msg)
+		}
+		if m.confirmingDiscard != "" {
+			return m, m.answerDiscardPrompt(msg)
+		}
+		if m.resizing {
+			return m, m.answerResize(msg)
+		}
+		if m.commandLog != nil {
+			return m, m.answerCommandLog(msg)
+		}
+		if m.keyHelp != nil {
+			return m, m.answerKeyHelp(msg)
+		}
+
 		// Handle text input mode first
 		if m.textInputMode != "" {
 			switch msg.String() {
@@ -179,11 +421,39 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.textInputMode = ""
 					m.textInput.Blur()
 					if mode == "pickaxe" {
+						m.pushHistory()
 						m.pickaxeTerm = value
-						m.sourceMode = sourcePickaxe
+						m.switchSource(sourcePickaxe)
 						m.sourceIndex = 0
 						m.updateSourceIndicator()
-						return m, m.loadPickaxeCommits
+						return m, m.trackOperation("Searching history", (*Model).loadPickaxeCommits)
+					}
+					if mode == "pr" {
+						return m, m.loadPRView(value)
+					}
+					if mode == "fetch" {
+						return m, m.fetchAndReview(value)
+					}
+					if mode == "glob" {
+						return m, m.setGlob(value)
+					}
+					if mode == "compare" {
+						return m, m.loadFileCompare(value)
+					}
+					if mode == "owner" {
+						return m, m.setOwnerFilter(value)
+					}
+					if mode == "tag" {
+						return m, m.loadTagCompare(value)
+					}
+					if mode == "author" {
+						return m, m.loadAuthorLines(value)
+					}
+					if mode == "range" {
+						return m, m.loadBlameRange(value)
+					}
+					if mode == "refdiff" {
+						return m, m.loadRefDiff(value)
 					}
 				}
 				m.textInputMode = ""
@@ -200,23 +470,63 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// esc abandons a slow load before doing anything else
+		if m.operation != nil && msg.String() == "esc" {
+			return m, m.cancelOperation()
+		}
+
+		// Type-to-jump in the commit list captures all keys until enter or esc
+		if m.focus == focusCommitList && m.commitList.IsJumping() {
+			if msg.String() == "ctrl+c" {
+				return m, m.quit()
+			}
+			return m, m.updateCommitList(msg)
+		}
+
+		// Searching the tree captures all keys until enter or esc
+		if m.focus == focusFileTree && m.fileTree.IsSearching() {
+			if msg.String() == "ctrl+c" {
+				return m, m.quit()
+			}
+			prev := m.fileTree.SelectedPath()
+			m.fileTree, _ = m.fileTree.Update(msg)
+			if m.fileTree.SelectedPath() != prev {
+				return m, m.scheduleTreePreview()
+			}
+			return m, nil
+		}
+
+		// After z, z/t/b place the selection in its list and a/M/R fold the full-file view;
+		// any other key toggles the description first, then does what it usually does
+		if m.zPending {
+			m.zPending = false
+			if where, ok := recenterKeys[msg.String()]; ok && !m.sidebar.IsFiltering() {
+				m.recenter(where)
+				return m, nil
+			}
+			if cmd, ok := m.foldKey(msg.String()); ok {
+				return m, cmd
+			}
+			toggle := m.toggleDescription()
+			next, cmd := m.update(msg)
+			return next, tea.Batch(toggle, cmd)
+		}
+
 		switch msg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m, m.quit()
 		case "q":
 			if !m.sidebar.IsFiltering() {
 				if m.showFileTree {
-					m.showFileTree = false
-					m.setFocus(focusCommitList)
-					m.updateLayout()
-					return m, nil
+					return m, m.closeFileTree()
 				}
 				if m.singleFileMode {
 					// Exit single-file mode
+					m.pushHistory()
 					m.exitSingleFileMode()
 					return m, m.loadDiffForCurrentFile
 				}
-				return m, tea.Quit
+				return m, m.quit()
 			}
 		case "tab":
 			if !m.sidebar.IsFiltering() {
@@ -239,36 +549,88 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				return m, nil
 			}
+		case "shift+tab":
+			// Cycle focus backward
+			if !m.sidebar.IsFiltering() {
+				if m.showFileTree {
+					if m.focus == focusFileTree {
+						m.setFocus(focusDiffView)
+					} else {
+						m.setFocus(focusFileTree)
+					}
+				} else {
+					switch m.focus {
+					case focusCommitList:
+						m.setFocus(focusDiffView)
+					case focusFileList:
+						m.setFocus(focusCommitList)
+					case focusDiffView:
+						m.setFocus(focusFileList)
+					}
+				}
+				return m, nil
+			}
 		case "t":
 			// Toggle file tree (only in commits mode, not single-file, not filtering)
 			if !m.sidebar.IsFiltering() && !m.singleFileMode {
-				m.showFileTree = !m.showFileTree
 				if m.showFileTree {
-					m.setFocus(focusFileTree)
-					m.updateLayout()
-					return m, m.loadTreeFiles
+					return m, m.closeFileTree()
 				}
-				m.setFocus(focusCommitList)
+				m.showFileTree = true
+				m.setFocus(focusFileTree)
 				m.updateLayout()
+				return m, m.loadTreeFiles
+			}
+		case "ctrl+l":
+			// Reload just the current diff, after the file changed on disk
+			if !m.sidebar.IsFiltering() && !m.showFileTree {
+				return m, m.reloadDiff()
+			}
+		case "^":
+			// Switch the tree between HEAD and the selected commit
+			if m.showFileTree && !m.sidebar.IsFiltering() {
+				return m, m.toggleTreeRevision()
+			}
+		case "L":
+			// Toggle preview lock: browse lists without reloading the diff
+			if !m.sidebar.IsFiltering() {
+				m.previewLocked = !m.previewLocked
+				if !m.previewLocked && m.pendingLoad != nil {
+					cmd := m.pendingLoad
+					m.pendingLoad = nil
+					return m, cmd
+				}
 				return m, nil
 			}
 		case " ", "enter":
+			// Preview lock: enter confirms the pending selection
+			if msg.String() == "enter" && m.previewLocked && m.pendingLoad != nil && !m.sidebar.IsFiltering() {
+				cmd := m.pendingLoad
+				m.pendingLoad = nil
+				return m, cmd
+			}
+			// Refs palette: show the commits from the selected ref
+			if msg.String() == "enter" && m.repoView == viewRefs && m.focus == focusCommitList && !m.singleFileMode {
+				return m, m.gotoSelectedRef()
+			}
 			// File tree: select a file to enter single-file mode
 			if m.showFileTree && m.focus == focusFileTree && !m.fileTree.IsSelectedDir() {
 				selectedPath := m.fileTree.SelectedPath()
 				if selectedPath != "" {
+					m.pushHistory()
 					m.currentFile = selectedPath
 					m.showFileTree = false
 					m.enterSingleFileMode()
 					m.updateLayout()
-					return m, m.loadFileCommits
+					return m, m.trackOperation("Loading history", (*Model).loadFileCommits)
 				}
 				return m, nil
 			}
 			// Enter single-file mode from file list
 			if !m.sidebar.IsFiltering() && m.focus == focusFileList && m.currentFile != "" && !m.singleFileMode {
+				m.pushHistory()
 				m.enterSingleFileMode()
-				return m, m.loadFileCommits
+				return m, m.trackOperation("Loading history", (*Model).loadFileCommits)
 			}
 		case "]":
 			if !m.sidebar.IsFiltering() {
@@ -298,32 +660,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.loadFilesForCurrentCommit
 				}
 			}
-		case "1":
-			if !m.sidebar.IsFiltering() {
-				if m.showFileTree {
-					m.setFocus(focusFileTree)
-				} else {
-					m.setFocus(focusCommitList)
-				}
+		case "/":
+			// Type-to-jump in the commit list or search the tree; the file list handles its own filter
+			if m.focus == focusCommitList {
+				m.commitList.StartJump()
 				return m, nil
 			}
-		case "2":
-			if !m.sidebar.IsFiltering() {
-				if m.showFileTree {
-					m.setFocus(focusFileTree) // no panel 2 in tree mode
-				} else {
-					m.setFocus(focusFileList)
-				}
+			if m.focus == focusFileTree {
+				m.fileTree.StartSearch()
 				return m, nil
 			}
-		case "3":
+		case "1", "2", "3":
 			if !m.sidebar.IsFiltering() {
-				m.setFocus(focusDiffView)
+				if f, ok := m.focusForKey(msg.String()); ok {
+					m.setFocus(f)
+				}
 				return m, nil
 			}
 		case "c":
 			// Cycle display modes in single-file mode
 			if m.singleFileMode {
+				m.blameSplit = false
 				m.displayMode = (m.displayMode + 1) % 4
 				m.diffView.SetMode(true, int(m.displayMode))
 				return m, m.loadContentForCurrentSource()
@@ -331,23 +688,31 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "r":
 			// Toggle reflog source
 			if m.singleFileMode {
+				m.pushHistory()
 				if m.sourceMode == sourceReflog {
-					m.sourceMode = sourceCommits
+					m.switchSource(sourceCommits)
 					m.updateSourceIndicator()
 					m.updateSingleFileModeDisplay()
 					return m, m.loadContentForCurrentSource()
 				}
-				m.sourceMode = sourceReflog
-				m.reflogIndex = 0
+				m.switchSource(sourceReflog)
+				if m.reflogFile != m.currentFile {
+					m.reflogIndex = 0
+				}
 				m.updateSourceIndicator()
 				return m, m.loadReflog
 			}
+			// Outside single-file mode, browse HEAD's reflog
+			if !m.sidebar.IsFiltering() && !m.showFileTree {
+				return m, m.toggleHeadReflog()
+			}
 		case "s":
 			// Toggle pickaxe source
 			if m.singleFileMode {
 				if m.sourceMode == sourcePickaxe {
 					// Deactivate pickaxe
-					m.sourceMode = sourceCommits
+					m.pushHistory()
+					m.switchSource(sourceCommits)
 					m.pickaxeTerm = ""
 					m.updateSourceIndicator()
 					m.updateSingleFileModeDisplay()
@@ -360,33 +725,308 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textInputMode = "pickaxe"
 				return m, textinput.Blink
 			}
-		case "z":
+		case "m":
+			// Mark the current version for side-by-side comparison
+			if m.singleFileMode {
+				return m, m.markCommit()
+			}
+		case "M":
+			// Show the marked and current versions side by side
+			if m.singleFileMode {
+				return m, m.compareMarked()
+			}
+		case "V":
+			// Blame a range of lines from full-file view
+			if m.singleFileMode {
+				return m, m.promptBlameRange()
+			}
+		case "ctrl+a":
+			// Pick out one author's lines of the file, over a range of commits
+			if m.singleFileMode {
+				return m, m.promptAuthorLines()
+			}
+		case "ctrl+b":
+			// Compare the file against a tag
+			if m.singleFileMode {
+				return m, m.promptTagCompare()
+			}
+		case "b":
+			// Toggle blame beside the file, scrolling together
+			if m.singleFileMode {
+				return m, m.toggleBlameSplit()
+			}
+		case "*":
+			// Limit the commit and file lists to paths matching a glob
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.promptGlob()
+			}
+		case "@":
+			// Limit the files to those a CODEOWNERS handle owns
+			if !m.sidebar.IsFiltering() && !m.singleFileMode {
+				return m, m.toggleOwnerFilter()
+			}
+		case "H":
+			// Show how many commits have touched each listed file
+			if !m.sidebar.IsFiltering() && !m.singleFileMode {
+				return m, m.toggleCommitCounts()
+			}
+		case "T":
+			// Toggle textconv filters for files with a diff driver
+			if !m.sidebar.IsFiltering() {
+				return m, m.toggleTextconv()
+			}
+		case "x":
+			// Switch the diff renderer between the built-in one and delta
+			if !m.sidebar.IsFiltering() {
+				return m, m.toggleDiffRenderer()
+			}
+		case "D":
+			// Diff any two files, each from the working tree or a revision
+			if !m.sidebar.IsFiltering() && !m.showFileTree {
+				return m, m.promptCompare()
+			}
+		case "p":
+			// Pin the current file so it stays selected across commits
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.togglePin()
+			}
+		case "P", "R":
+			// Preview cherry-picking / reverting the selected commit onto HEAD
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree && m.repoView != viewStaged && m.repoView != viewWorkingCopy {
+				return m, m.loadPickPreview(msg.String() == "R")
+			}
+		case "&":
+			// Toggle showing how merge commits resolved their files
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.toggleMergeResolution()
+			}
+		case "=":
+			// Diff the selected commit against a typed ref
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.promptRefDiff()
+			}
+		case "ctrl+o":
+			// Jump back through navigation history (vim-style)
+			if !m.sidebar.IsFiltering() {
+				return m, m.navigateBack()
+			}
+		case "ctrl+n":
+			// Jump forward; ctrl+i is indistinguishable from tab in terminals
 			if !m.sidebar.IsFiltering() {
-				m.diffView.ToggleDescription()
+				return m, m.navigateForward()
+			}
+		case "ctrl+w":
+			// Toggle wrapping long commit subjects
+			if !m.sidebar.IsFiltering() {
+				m.wrapSubjects = !m.wrapSubjects
+				m.commitList.SetWrap(m.wrapSubjects)
+				m.updateLayout()
 				return m, nil
 			}
+		case "`":
+			// Show the git commands run so far
+			if !m.sidebar.IsFiltering() {
+				m.OpenCommandLog()
+				return m, nil
+			}
+		case "?":
+			// List every key of the current mode
+			if !m.sidebar.IsFiltering() {
+				m.OpenKeyHelp()
+				return m, nil
+			}
+		case "ctrl+t":
+			// Toggle day headers between commits
+			if !m.sidebar.IsFiltering() {
+				m.commitList.SetGroupByDate(!m.commitList.groupByDate)
+				return m, nil
+			}
+		case "$":
+			// Toggle each commit's added and deleted line counts
+			if !m.sidebar.IsFiltering() {
+				m.toggleCommitStats()
+				return m, nil
+			}
+		case "ctrl+s", "alt+s":
+			// Save the rendered view: plain text, or with ANSI styling when alt is held
+			if !m.sidebar.IsFiltering() {
+				return m, m.saveRenderedView(msg.String() == "alt+s")
+			}
+		case "S":
+			// Cycle the stash view: vs parent, vs working tree, off
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.cycleStashView()
+			}
+			// Interleave the stashes that touched the file into its history
+			if !m.sidebar.IsFiltering() && m.singleFileMode && m.sourceMode == sourceCommits {
+				return m, m.toggleFileStashes()
+			}
+		case "F":
+			// Cycle the conventional-commit type filter
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree && m.repoView == viewCommits {
+				return m, m.cycleTypeFilter()
+			}
+		case "B":
+			// Review a branch as a pull request against its base
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.promptPRView()
+			}
+		case "ctrl+f":
+			// Fetch a remote ref and review it as a pull request
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.promptFetch()
+			}
+		case "a":
+			// Toggle listing the commits of every branch
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.toggleAllRefs()
+			}
+		case "J":
+			// Toggle the palette of branches and tags
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.toggleRefs()
+			}
+		case "K":
+			// List the commits from a branch or tag on the selected commit
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.gotoCommitRef()
+			}
+		case "ctrl+k":
+			// Copy the name of a branch or tag on the selected commit
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.copyCommitRef()
+			}
+		case "w":
+			// Toggle the view of everything changed since HEAD
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.toggleWorkingCopyView()
+			}
+		case "i":
+			// Toggle the staged changes view
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.toggleStagedView()
+			}
+		case "|":
+			// Adjust the split between the left column and the diff with the arrow keys
+			if !m.sidebar.IsFiltering() {
+				m.startResize()
+				return m, nil
+			}
+		case "%":
+			// Cycle the file list through added, deleted, modified and renamed files only
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && !m.showFileTree {
+				return m, m.cycleStatusFilter()
+			}
+		case "+":
+			// Stage the selected file
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && m.repoView == viewWorkingCopy && m.focus == focusFileList {
+				return m, m.stageCurrentFile()
+			}
+		case "-":
+			// Unstage the selected file
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && (m.repoView == viewWorkingCopy || m.repoView == viewStaged) && m.focus == focusFileList {
+				return m, m.unstageCurrentFile()
+			}
+		case "!":
+			// Discard the selected file's working tree changes, after confirmation
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && m.repoView == viewWorkingCopy && m.focus == focusFileList {
+				return m, m.promptDiscard()
+			}
+		case "U":
+			// Unstage the hunk at the top of the diff view
+			if !m.sidebar.IsFiltering() && !m.singleFileMode && m.repoView == viewStaged {
+				return m, m.unstageCurrentHunk()
+			}
+		case "#":
+			// Toggle the line number gutter
+			if !m.sidebar.IsFiltering() {
+				if m.diffView.ToggleLineNumbers() {
+					return m, m.setStatus("Line numbers shown")
+				}
+				return m, m.setStatus("Line numbers hidden")
+			}
+		case "e":
+			// Show long lines in full or shortened
+			if !m.sidebar.IsFiltering() {
+				if m.diffView.ToggleLongLines() {
+					return m, m.setStatus("Long lines shown in full")
+				}
+				return m, m.setStatus("Long lines shortened")
+			}
+		case "E":
+			// Toggle carriage return markers (␍) for CRLF files
+			if !m.sidebar.IsFiltering() {
+				if m.diffView.ToggleLineEndings() {
+					return m, m.setStatus("Line endings shown as " + crMarker)
+				}
+				return m, m.setStatus("Line endings hidden")
+			}
+		case "Y":
+			// Copy the current hunk as a GitHub suggestion block
+			if !m.sidebar.IsFiltering() {
+				return m, m.copySuggestion()
+			}
+		case "y":
+			// Copy a file:line reference to the top line for a review comment
+			if !m.sidebar.IsFiltering() {
+				return m, m.copyLineReference()
+			}
+		case "ctrl+g":
+			// Copy a git show command reproducing the diff being viewed
+			if !m.sidebar.IsFiltering() && !m.showFileTree {
+				return m, m.copyShowCommand()
+			}
+		case "ctrl+y":
+			// Copy the whole file as of the viewed commit
+			if m.singleFileMode {
+				if m.displayMode != displayFull {
+					return m, m.setStatus("Copy the file from full mode (c)")
+				}
+				return m, m.copyFileContent()
+			}
+		case "O":
+			// Go to the commit that introduced the line at the top of the diff view
+			if !m.sidebar.IsFiltering() {
+				return m, m.findLineOrigin()
+			}
+		case "o":
+			// Pipe the current diff into the external pager
+			if !m.sidebar.IsFiltering() && m.diffView.RawContent() != "" {
+				return m, m.openInPager()
+			}
+		case "z":
+			if !m.sidebar.IsFiltering() {
+				return m, m.startZ()
+			}
 		case "esc":
 			if !m.sidebar.IsFiltering() {
 				if m.showFileTree {
-					m.showFileTree = false
-					m.setFocus(focusCommitList)
-					m.updateLayout()
-					return m, nil
+					return m, m.closeFileTree()
 				}
 				if m.singleFileMode {
 					// If a source is active, deactivate it first
 					if m.sourceMode != sourceCommits {
-						m.sourceMode = sourceCommits
+						m.pushHistory()
+						m.switchSource(sourceCommits)
 						m.pickaxeTerm = ""
 						m.updateSourceIndicator()
 						m.updateSingleFileModeDisplay()
 						return m, m.loadContentForCurrentSource()
 					}
 					// Exit single-file mode
+					m.pushHistory()
 					m.exitSingleFileMode()
 					return m, m.loadDiffForCurrentFile
+				} else if m.preview != nil {
+					return m, m.exitPreview()
+				} else if m.refDiff != nil {
+					return m, m.exitRefDiff()
+				} else if m.gitService.Pathspec() != "" {
+					// Lift the glob limit
+					return m, m.setGlob("")
 				} else if m.commitIndex > 0 {
 					// Return to latest commit
+					m.pushHistory()
 					m.commitIndex = 0
 					return m, m.loadFilesForCurrentCommit
 				}
@@ -396,39 +1036,32 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Route to focused component
 		if m.focus == focusFileTree {
 			var cmd tea.Cmd
+			prevPath := m.fileTree.SelectedPath()
+			wasExpanded := m.fileTree.IsExpanded(prevPath)
 			m.fileTree, cmd = m.fileTree.Update(msg)
 			cmds = append(cmds, cmd)
-		} else if m.focus == focusCommitList {
-			var cmd tea.Cmd
-			prevIdx := m.commitList.SelectedIndex()
-			m.commitList, cmd = m.commitList.Update(msg)
-			cmds = append(cmds, cmd)
-
-			// Check if commit selection changed
-			newIdx := m.commitList.SelectedIndex()
-			if newIdx != prevIdx {
-				if m.singleFileMode {
-					// In single-file mode, navigate file history
-					m.fileCommitIndex = newIdx
-					m.updateSingleFileModeDisplay()
-					cmds = append(cmds, m.loadContentForCurrentSource())
-				} else {
-					// In commits mode, load files for selected commit
-					m.commitIndex = newIdx
-					cmds = append(cmds, m.loadFilesForCurrentCommit)
-				}
+			if m.fileTree.SelectedPath() != prevPath {
+				cmds = append(cmds, m.scheduleTreePreview())
+			} else if m.fileTree.IsSelectedDir() && !wasExpanded && m.fileTree.IsExpanded(prevPath) {
+				// Opening a directory fetches its files' previews ahead of moving onto them
+				cmds = append(cmds, m.prefetchTreeDir(prevPath))
 			}
+		} else if m.focus == focusCommitList {
+			cmds = append(cmds, m.updateCommitList(msg))
 		} else if m.sidebar.IsFiltering() || m.focus == focusFileList {
 			var cmd tea.Cmd
 			prevSelected := m.sidebar.SelectedItem()
 			m.sidebar, cmd = m.sidebar.Update(msg)
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, m.loadCommitCounts())
 
 			// Check if selection changed
 			currSelected := m.sidebar.SelectedItem()
 			if currSelected != nil && (prevSelected == nil || prevSelected.Path != currSelected.Path) {
 				m.currentFile = currSelected.Path
-				cmds = append(cmds, m.loadDiffForCurrentFile)
+				if !m.singleFileMode {
+					m.updateRevisionDisplay()
+				}
+				cmds = append(cmds, m.queueLoad(m.loadDiffForCurrentFile))
 			}
 		} else if m.focus == focusDiffView {
 			var cmd tea.Cmd
@@ -440,39 +1073,107 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.updateLayout()
+		if m.commandLog != nil {
+			m.refreshCommandLog()
+		}
+		if m.keyHelp != nil {
+			m.resizeKeyHelp()
+		}
+		cmds = append(cmds, m.loadCommitCounts())
 
 	case initialDataMsg:
-		m.commits = msg.commits
-		m.populateCommitList(msg.commits)
-		m.commitList.SelectIndex(m.commitIndex)
-		m.sidebar.SetItems(msg.files)
-		if len(msg.files) > 0 {
-			m.currentFile = msg.files[0].Path
+		m.headLabel = msg.headLabel
+		m.allCommits = msg.commits
+		m.moreCommits = len(msg.commits) == commitPageSize
+		m.applyTypeFilter()
+		if m.typeFilter != "" {
+			// The preloaded files belong to the newest commit, which may be filtered out
+			cmds = append(cmds, m.loadFilesForCurrentCommit)
+			break
+		}
+		files := m.filterByStatus(m.ownedFiles(msg.files))
+		m.sidebar.SetItems(files)
+		cmds = append(cmds, m.loadCommitCounts())
+		if len(files) > 0 {
+			m.currentFile = files[0].Path
 			cmds = append(cmds, m.loadDiffForCurrentFile)
+		} else {
+			m.currentFile = ""
+			m.diffView.SetBanner("")
+			if len(msg.commits) == 0 {
+				m.diffView.SetContent("No commits to show")
+			} else {
+				m.diffView.SetContent("No files changed in this commit")
+			}
 		}
 		m.updateRevisionDisplay()
 
 	case filesLoadedMsg:
-		m.sidebar.SetItems(msg.files)
-		if len(msg.files) > 0 {
-			m.currentFile = msg.files[0].Path
+		m.preview = nil
+		m.refDiff = nil
+		files := m.filterByStatus(m.ownedFiles(msg.files))
+		m.sidebar.SetItems(files)
+		cmds = append(cmds, m.loadCommitCounts())
+		target := m.restoreFile
+		if target == "" {
+			target = m.pinnedFile
+		}
+		m.restoreFile = ""
+		switch {
+		case target != "" && m.sidebar.SelectPath(target):
+			m.currentFile = target
 			cmds = append(cmds, m.loadDiffForCurrentFile)
-		} else {
+		case m.pinnedFile != "":
+			// Stay on the pinned file even where the commit doesn't touch it
+			m.currentFile = m.pinnedFile
+			m.diffView.SetBanner("")
+			m.diffView.SetContent(m.pinnedFile + " not changed in this commit")
+		case len(files) > 0:
+			m.currentFile = files[0].Path
+			cmds = append(cmds, m.loadDiffForCurrentFile)
+		default:
 			m.currentFile = ""
-			m.diffView.SetContent("No files changed in this commit")
+			m.diffView.SetBanner("")
+			if m.repoView == viewStaged {
+				m.diffView.SetContent("Nothing staged")
+			} else if m.repoView == viewWorkingCopy {
+				m.diffView.SetContent("No changes since HEAD")
+			} else if m.statusFilter != "" {
+				m.diffView.SetContent("No " + m.statusFilterName() + " files in this commit")
+			} else if m.ownerFilter != "" {
+				m.diffView.SetContent("No files owned by " + m.ownerFilter + " in this commit")
+			} else {
+				m.diffView.SetContent("No files changed in this commit")
+			}
 		}
 		m.updateRevisionDisplay()
 
 	case fileCommitsLoadedMsg:
 		m.fileCommits = msg.commits
+		if msg.selected != "" {
+			m.fileCommitIndex = 0
+			for i, c := range msg.commits {
+				if c.Hash == msg.selected {
+					m.fileCommitIndex = i
+				}
+			}
+		}
 		m.populateCommitList(msg.commits)
-		m.commitList.SetTitle("History")
+		if m.fileStashes {
+			m.commitList.SetTitle("History + stashes")
+		} else {
+			m.commitList.SetTitle("History")
+		}
 		m.commitList.SelectIndex(m.fileCommitIndex)
 		m.updateSingleFileModeDisplay()
 		cmds = append(cmds, m.loadContentForCurrentSource())
 
 	case reflogLoadedMsg:
 		m.reflogEntries = msg.entries
+		m.reflogFile = m.currentFile
+		if m.reflogIndex >= len(msg.entries) {
+			m.reflogIndex = 0
+		}
 		m.populateCommitList(msg.entries)
 		m.commitList.SetTitle("Reflog")
 		m.commitList.SelectIndex(m.reflogIndex)
@@ -481,11 +1182,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case sourceCommitsLoadedMsg:
 		if msg.err != nil || len(msg.commits) == 0 {
-			errMsg := "No commits found"
+			errMsg := fmt.Sprintf("No commits changed %q in %s", m.pickaxeTerm, m.currentFile)
 			if msg.err != nil {
-				errMsg = fmt.Sprintf("Error: %v", msg.err)
+				errMsg = fmt.Sprintf("Search for %q failed: %v", m.pickaxeTerm, msg.err)
 			}
-			m.sourceMode = sourceCommits
+			m.switchSource(sourceCommits)
+			m.pendingOffset = -1
 			m.pickaxeTerm = ""
 			m.updateSourceIndicator()
 			m.updateSingleFileModeDisplay()
@@ -493,17 +1195,154 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		} else {
 			m.sourceCommits = msg.commits
 			m.populateCommitList(msg.commits)
-			m.commitList.SetTitle(fmt.Sprintf("S:\"%s\"", m.pickaxeTerm))
+			m.commitList.SetTitle(fmt.Sprintf("S:\"%s\" (%d)", m.pickaxeTerm, len(msg.commits)))
 			m.commitList.SelectIndex(m.sourceIndex)
 			m.updateSourceDisplay()
 			cmds = append(cmds, m.loadContentForCurrentSource())
 		}
 
 	case treeFilesLoadedMsg:
-		m.fileTree.SetFiles(msg.paths)
+		m.treeHead = msg.head
+		m.fileTree.SetSparse(msg.sparse)
+		if msg.rev != "" {
+			m.fileTree.SetRevision(msg.rev)
+		}
+		m.fileTree.SetFiles(m.ownedPaths(msg.paths))
+		cmds = append(cmds, m.scheduleTreePreview())
+
+	case zTimeoutMsg:
+		if msg.id == m.zID && m.zPending {
+			m.zPending = false
+			cmds = append(cmds, m.toggleDescription())
+		}
+
+	case treePreviewMsg:
+		if msg.id == m.treePreviewID && m.showFileTree && msg.path == m.fileTree.SelectedPath() {
+			cmds = append(cmds, m.loadTreePreview(msg.path))
+		}
+
+	case operationDoneMsg:
+		if m.finishOperation(msg) {
+			return m.Update(msg.msg)
+		}
+
+	case diffRenderedMsg:
+		m.diffView.applyRender(msg)
+
+	case deltaRenderedMsg:
+		m.diffView.applyDelta(msg)
+
+	case spinner.TickMsg:
+		cmds = append(cmds, m.updateSpinner(msg))
+
+	case workingCopyStartMsg:
+		m.headLabel = msg.headLabel
+		cmds = append(cmds, m.showWorkingCopy())
+
+	case sidebarRatioSavedMsg:
+		cmds = append(cmds, m.applySidebarRatioSaved(msg))
+
+	case lastChangeMsg:
+		m.applyLastChange(msg)
+
+	case fileActionMsg:
+		cmds = append(cmds, m.applyFileAction(msg))
+
+	case showCommandMsg:
+		cmds = append(cmds, m.applyShowCommand(msg))
+
+	case fileContentMsg:
+		cmds = append(cmds, m.applyFileContent(msg))
+
+	case commitsPageMsg:
+		m.applyCommitsPage(msg)
+
+	case refsLoadedMsg:
+		cmds = append(cmds, m.applyRefs(msg))
+
+	case headReflogLoadedMsg:
+		cmds = append(cmds, m.applyHeadReflog(msg))
+
+	case codeOwnersLoadedMsg:
+		m.codeOwners = msg.owners
+
+	case commitCountsMsg:
+		m.applyCommitCounts(msg)
+
+	case commitStatsMsg:
+		m.applyCommitStats(msg)
 
 	case diffLoadedMsg:
+		m.diffView.SetBanner(msg.banner)
+		m.diffView.SetAuthorLines(msg.authorLines)
 		m.diffView.SetContent(msg.content)
+		cmds = append(cmds, m.loadTagNotes(), m.loadCommitNote())
+		if m.pendingOffset >= 0 {
+			m.diffView.SetYOffset(m.pendingOffset)
+			m.pendingOffset = -1
+		} else {
+			m.scrollToFirstChange(msg)
+		}
+
+	case tagNotesMsg:
+		m.diffView.SetTagNotes(msg.notes)
+
+	case commitNoteMsg:
+		m.diffView.SetCommitNote(msg.note)
+
+	case headCheckedMsg:
+		cmds = append(cmds, m.applyHeadChecked(msg))
+
+	case commitsRefreshedMsg:
+		cmds = append(cmds, m.applyCommitsRefreshed(msg))
+
+	case sideBySideLoadedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Side-by-side view failed: %v", msg.err)))
+		} else {
+			m.diffView.SetBanner("")
+			m.diffView.SetSideBySide(msg.view)
+		}
+
+	case fileCompareMsg:
+		cmds = append(cmds, m.applyFileCompare(msg))
+
+	case nearestTagMsg:
+		m.applyNearestTag(msg)
+
+	case tagCompareMsg:
+		cmds = append(cmds, m.applyTagCompare(msg))
+
+	case lineOriginMsg:
+		cmds = append(cmds, m.applyLineOrigin(msg))
+
+	case stashesLoadedMsg:
+		cmds = append(cmds, m.applyStashes(msg))
+
+	case prViewLoadedMsg:
+		cmds = append(cmds, m.applyPRView(msg))
+
+	case prDivergenceMsg:
+		m.applyPRDivergence(msg)
+
+	case hunkUnstagedMsg:
+		cmds = append(cmds, m.applyHunkUnstaged(msg))
+
+	case previewLoadedMsg:
+		cmds = append(cmds, m.applyPreview(msg))
+
+	case refDiffLoadedMsg:
+		cmds = append(cmds, m.applyRefDiff(msg))
+
+	case pagerFinishedMsg:
+		if msg.err != nil {
+			cmds = append(cmds, m.setStatus(fmt.Sprintf("Pager failed: %v", msg.err)))
+		}
+
+	case statusClearMsg:
+		if msg.id == m.statusID {
+			m.statusMsg = ""
+		}
 
 	case ErrorMsg:
 		m.err = msg.Err
@@ -512,6 +1351,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// updateCommitList routes a key to the commit list and loads the newly selected commit
+func (m *Model) updateCommitList(msg tea.KeyMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	var cmd tea.Cmd
+	prevIdx := m.commitList.SelectedIndex()
+	m.commitList, cmd = m.commitList.Update(msg)
+	cmds = append(cmds, cmd)
+
+	// Check if commit selection changed
+	newIdx := m.commitList.SelectedIndex()
+	if newIdx != prevIdx {
+		if m.singleFileMode {
+			// In single-file mode, navigate file history
+			m.fileCommitIndex = newIdx
+			m.updateSingleFileModeDisplay()
+			cmds = append(cmds, m.queueLoad(m.loadContentForCurrentSource()))
+		} else {
+			// In commits mode, load files for selected commit
+			m.commitIndex = newIdx
+			cmds = append(cmds, m.queueLoad(m.loadFilesForCurrentCommit))
+			if newIdx >= len(m.commits)-1 {
+				cmds = append(cmds, m.loadMoreCommits())
+			}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
+// queueLoad returns cmd, or holds it until confirmed with enter while the preview is locked
+func (m *Model) queueLoad(cmd tea.Cmd) tea.Cmd {
+	if m.previewLocked {
+		m.pendingLoad = cmd
+		return nil
+	}
+	return cmd
+}
+
+// setStatus shows a transient message in the help bar and schedules its removal
+func (m *Model) setStatus(text string) tea.Cmd {
+	m.statusMsg = text
+	m.statusID++
+	id := m.statusID
+	return tea.Tick(3*time.Second, func(time.Time) tea.Msg {
+		return statusClearMsg{id: id}
+	})
+}
+
 func (m *Model) setFocus(f focus) {
 	m.focus = f
 	m.commitList.SetFocused(f == focusCommitList)
@@ -520,8 +1406,46 @@ func (m *Model) setFocus(f focus) {
 	m.fileTree.SetFocused(f == focusFileTree)
 }
 
+// closeFileTree leaves tree mode and replaces any tree preview with the current file's diff
+func (m *Model) closeFileTree() tea.Cmd {
+	m.showFileTree = false
+	m.setFocus(focusCommitList)
+	m.updateLayout()
+	m.updateRevisionDisplay()
+	return m.loadDiffForCurrentFile
+}
+
+// focusForKey maps the panel number keys to panels in the current layout.
+// 1 is always the top-left list (commits, or the tree in tree mode), 2 the
+// file list (absent in tree mode) and 3 the diff view.
+func (m *Model) focusForKey(key string) (focus, bool) {
+	switch key {
+	case "1":
+		if m.showFileTree {
+			return focusFileTree, true
+		}
+		return focusCommitList, true
+	case "2":
+		if m.showFileTree {
+			return 0, false
+		}
+		return focusFileList, true
+	case "3":
+		return focusDiffView, true
+	}
+	return 0, false
+}
+
 func (m *Model) enterSingleFileMode() {
 	m.singleFileMode = true
+	m.markedCommit = ""
+	m.blameSplit = false
+	m.sourceOffsets = make(map[sourceMode]int)
+	// Each file opens in its configured mode, or the diff, whatever the last one used
+	m.displayMode = displayDiff
+	if dm, ok := parseDisplayMode(m.config.DisplayModeFor(m.currentFile)); ok {
+		m.displayMode = dm
+	}
 	m.fileCommitIndex = 0
 	m.setFocus(focusDiffView)
 	m.diffView.SetMode(true, int(m.displayMode))
@@ -539,11 +1463,25 @@ func (m *Model) exitSingleFileMode() {
 	m.diffView.SetSourceIndicator("")
 	// Restore repo commits in commit list
 	m.populateCommitList(m.commits)
-	m.commitList.SetTitle("Commits")
+	m.commitList.SetTitle(m.commitListTitle())
 	m.commitList.SelectIndex(m.commitIndex)
 	m.updateRevisionDisplay()
 }
 
+// switchSource changes the commit source, remembering the diff scroll position
+// of the current source and restoring the one last used for the new source
+func (m *Model) switchSource(mode sourceMode) {
+	m.sourceOffsets[m.sourceMode] = m.diffView.YOffset()
+	m.sourceMode = mode
+	m.pendingOffset = m.sourceOffsets[mode]
+	if mode == sourceCommits {
+		// Put the file history back in the list at the remembered selection
+		m.populateCommitList(m.fileCommits)
+		m.commitList.SetTitle("History")
+		m.commitList.SelectIndex(m.fileCommitIndex)
+	}
+}
+
 // syncCommitListToIndex updates the commit list selection to match the current index
 func (m *Model) syncCommitListToIndex() {
 	switch m.sourceMode {
@@ -564,9 +1502,10 @@ func (m *Model) syncCommitListToIndex() {
 func (m *Model) populateCommitList(commits []git.Commit) {
 	items := make([]CommitItem, len(commits))
 	for i, c := range commits {
-		items[i] = CommitItem{Hash: c.Hash, Message: c.Message}
+		items[i] = CommitItem{Hash: c.Hash, Message: c.Message, Date: c.Date, Stash: git.IsStashRef(c.Ref), Author: c.Author}
 	}
 	m.commitList.SetItems(items)
+	m.commitStatsDue = true
 }
 
 func (m *Model) updateSourceIndicator() {
@@ -659,20 +1598,103 @@ func (m *Model) loadContentForCurrentSource() tea.Cmd {
 	file := m.currentFile
 	dm := m.displayMode
 
+	if m.blameSplit {
+		cmd := m.trackOperation("Blaming "+file, func(op *Model) tea.Msg {
+			return op.loadBlameSplit(file, hash)
+		})
+		m.operation.content = true
+		return cmd
+	}
+
+	if m.sourceMode == sourceReflog && (dm == displayDiff || dm == displayContext) {
+		if ref := m.reflogEntries[m.reflogIndex].Ref; ref != "" {
+			return func() tea.Msg {
+				return m.loadReflogStep(file, ref, hash, dm)
+			}
+		}
+	}
+
+	if stash, ok := m.currentFileStash(); ok && (dm == displayDiff || dm == displayContext) {
+		return func() tea.Msg {
+			return m.loadFileStash(file, stash)
+		}
+	}
+
+	load := func(op *Model) tea.Msg {
+		return op.loadContentForCommit(file, hash, dm)
+	}
+	if dm == displayBlame {
+		// Blame walks the file's whole history, which can take a while
+		cmd := m.trackOperation("Blaming "+file, load)
+		m.operation.content = true
+		return cmd
+	}
+	if m.operation != nil && m.operation.content {
+		// A pending blame would otherwise overwrite this content when it finishes
+		m.operation = nil
+	}
 	return func() tea.Msg {
+		return load(m)
+	}
+}
+
+// loadReflogStep shows what a reflog entry changed in the file relative to the
+// previous entry, falling back to the commit's own patch for the oldest entry
+func (m *Model) loadReflogStep(file, ref, hash string, dm displayMode) tea.Msg {
+	context := 3
+	if dm == displayContext {
+		context = 10
+	}
+	diff, err := m.gitService.GetReflogEntryDiffWithContext(file, ref, context)
+	if err != nil {
 		return m.loadContentForCommit(file, hash, dm)
 	}
+	// An amend may have reworded the message as well as (or instead of) changing the file
+	var messageDiff string
+	if prev, err := git.PreviousReflogRef(ref); err == nil {
+		messageDiff, _ = m.gitService.GetCommitMessageDiff(ref, prev)
+	}
+	if diff == "" && messageDiff == "" {
+		return diffLoadedMsg{content: "File unchanged by this reflog step"}
+	}
+	if messageDiff != "" {
+		diff = withMessageDiff(messageDiff, diff)
+	}
+	return diffLoadedMsg{content: diff, banner: "Changes since the previous reflog entry"}
+}
+
+// withMessageDiff puts a commit message diff above a file diff. Both lose their headers,
+// so the message hunk is labelled to tell it apart.
+func withMessageDiff(messageDiff, fileDiff string) string {
+	header, body, _ := strings.Cut(stripDiffHeader(messageDiff), "\n")
+	content := header + " commit message\n" + strings.TrimRight(body, "\n")
+	if fileDiff == "" {
+		return content
+	}
+	return content + "\n" + stripDiffHeader(fileDiff)
 }
 
 func (m *Model) loadContentForCommit(file, hash string, dm displayMode) tea.Msg {
 	var content string
 	var err error
+	var firstChange int
+
+	status, _ := m.gitService.GetFileStatusAtCommit(file, hash)
+	deleted := status == "D"
 
 	switch dm {
 	case displayBlame:
-		content, err = m.gitService.GetBlame(file, hash)
+		blameRev := hash
+		if deleted {
+			// The file no longer exists at this commit, so blame its last version
+			blameRev = hash + "^"
+		}
+		content, err = m.gitService.GetBlame(file, blameRev)
 	case displayFull:
 		content, err = m.gitService.GetFileContentAtCommit(file, hash)
+		if err == nil && m.config.ScrollToFirstChange != "off" {
+			firstChange = m.firstChangedLine(file, hash)
+		}
 	case displayContext:
 		content, err = m.gitService.GetDiffAtCommitWithContext(file, hash, 10)
 	default: // displayDiff
@@ -682,14 +1704,56 @@ func (m *Model) loadContentForCommit(file, hash string, dm displayMode) tea.Msg
 	if err != nil {
 		return diffLoadedMsg{content: fmt.Sprintf("Error: %v", err)}
 	}
+	if dm == displayDiff || dm == displayContext {
+		if summary := m.summarizeSubmodule(file, content); summary != content {
+			return diffLoadedMsg{content: summary}
+		}
+		if !hasHunk(content) {
+			return m.emptyDiffMsg(file, hash, status, content)
+		}
+	}
 	if content == "" {
 		return diffLoadedMsg{content: "No changes to display"}
 	}
-	return diffLoadedMsg{content: content}
+	if deleted {
+		return diffLoadedMsg{content: content, banner: deletedBanner(dm)}
+	}
+	return diffLoadedMsg{content: content, firstChange: firstChange}
+}
+
+// emptyDiffMsg explains why a commit's diff of a file has no hunks
+func (m *Model) emptyDiffMsg(file, hash, status, content string) diffLoadedMsg {
+	switch {
+	case status == "" && !m.gitService.FileExistsAtCommit(file, hash):
+		return diffLoadedMsg{content: "File does not exist at this commit"}
+	case status == "":
+		return diffLoadedMsg{content: "File not changed in this commit"}
+	case strings.Contains(stripANSI(content), "Binary files"):
+		return diffLoadedMsg{content: "Binary file changed — no text diff"}
+	case describeModeChange(content) != "":
+		// The diff view turns the header's mode lines into a readable note
+		return diffLoadedMsg{content: content}
+	case status == "A":
+		return diffLoadedMsg{content: "Empty file added"}
+	case status == "D":
+		return diffLoadedMsg{content: "Empty file deleted"}
+	default:
+		return diffLoadedMsg{content: "No changes to display"}
+	}
+}
+
+// deletedBanner explains what is shown for a file removed by the viewed commit
+func deletedBanner(dm displayMode) string {
+	switch dm {
+	case displayFull, displayBlame:
+		return "File deleted in this commit — showing its content before deletion"
+	default:
+		return "File deleted in this commit — all prior lines removed"
+	}
 }
 
 func (m *Model) updateLayout() {
-	sidebarWidth := int(float64(m.width) * 0.20)
+	sidebarWidth := int(float64(m.width) * m.sidebarRatio)
 	diffWidth := m.width - sidebarWidth - 4
 
 	if m.showFileTree {
@@ -712,13 +1776,25 @@ func (m *Model) updateLayout() {
 }
 
 func (m *Model) updateRevisionDisplay() {
-	if m.commitIndex < len(m.commits) {
+	if m.refDiff != nil {
+		m.sidebar.SetRevision(m.refDiff.label)
+		m.diffView.SetFileInfo(m.currentFileLabel(), m.commitIndex, len(m.commits), m.refDiff.label)
+	} else if m.commitIndex < len(m.commits) {
 		commit := m.commits[m.commitIndex]
 		m.sidebar.SetRevision(commit.Hash)
-		m.diffView.SetFileInfo(m.currentFile, m.commitIndex, len(m.commits), commit.Hash)
+		m.diffView.SetFileInfo(m.currentFileLabel(), m.commitIndex, len(m.commits), commit.Hash)
 	}
 }
 
+// currentFileLabel names the current file for the diff header, with its old path if renamed or copied
+func (m *Model) currentFileLabel() string {
+	item := m.sidebar.SelectedItem()
+	if item == nil || item.Path != m.currentFile || item.OldPath == "" {
+		return m.currentFile
+	}
+	return fmt.Sprintf("%s → %s (%d%%)", item.OldPath, item.Path, item.Similarity)
+}
+
 func (m *Model) updateSingleFileModeDisplay() {
 	if m.fileCommitIndex < len(m.fileCommits) {
 		commit := m.fileCommits[m.fileCommitIndex]
@@ -749,6 +1825,10 @@ func (m *Model) updateSourceDisplay() {
 
 func (m *Model) loadFileCommits() tea.Msg {
 	commits, _ := m.gitService.GetFileCommits(m.currentFile)
+	if m.fileStashes {
+		stashes, _ := m.gitService.GetFileStashes(m.currentFile)
+		commits = withStashes(commits, stashes)
+	}
 	return fileCommitsLoadedMsg{commits: commits}
 }
 
@@ -763,23 +1843,62 @@ func (m *Model) loadPickaxeCommits() tea.Msg {
 }
 
 func (m *Model) loadTreeFiles() tea.Msg {
+	if rev := m.treeRevision(); rev != "" {
+		// The commit's own tree; the sparse checkout only applies to HEAD's
+		paths, err := m.gitService.GetTreeFiles(rev)
+		if err != nil {
+			return treeFilesLoadedMsg{paths: nil}
+		}
+		return treeFilesLoadedMsg{paths: paths, head: rev, rev: rev}
+	}
 	// Use HEAD for the tree
+	head, _ := m.gitService.GetHead()
 	paths, err := m.gitService.GetTreeFiles("HEAD")
 	if err != nil {
 		return treeFilesLoadedMsg{paths: nil}
 	}
-	return treeFilesLoadedMsg{paths: paths}
+	sparse := m.gitService.IsSparseCheckout()
+	if sparse {
+		paths = m.checkedOutPaths(paths)
+	}
+	return treeFilesLoadedMsg{paths: paths, head: head, sparse: sparse}
 }
 
 func (m *Model) loadFilesForCurrentCommit() tea.Msg {
 	var files []FileItem
 
-	if m.commitIndex < len(m.commits) {
+	if m.repoView == viewStaged {
+		stagedFiles, _ := m.gitService.GetStagedFiles()
+		for _, f := range stagedFiles {
+			files = append(files, FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity})
+		}
+	} else if m.repoView == viewWorkingCopy {
+		files = m.workingCopyFiles()
+	} else if m.repoView == viewReflog && m.commitIndex < len(m.commits) {
+		stepFiles, _ := m.gitService.GetReflogStepFiles(m.commits[m.commitIndex].Ref)
+		for _, f := range stepFiles {
+			files = append(files, FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity})
+		}
+	} else if m.inPRDiff() {
+		for _, f := range m.prView.Files {
+			files = append(files, FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity})
+		}
+	} else if m.repoView == viewStashes && m.commitIndex < len(m.commits) {
+		stashFiles, _ := m.gitService.GetStashFiles(m.commits[m.commitIndex].Hash, m.stashBase)
+		for _, f := range stashFiles {
+			files = append(files, FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity})
+		}
+	} else if m.commitIndex < len(m.commits) {
 		commit := m.commits[m.commitIndex]
+		if m.mergeResolution {
+			if mergeFiles, ok := m.loadMergeFiles(commit); ok {
+				return filesLoadedMsg{files: mergeFiles}
+			}
+		}
 		commitFiles, _ := m.gitService.GetFilesInCommit(commit.Hash)
 		stats, _ := m.gitService.GetNumstatForCommit(commit.Hash)
 		for _, f := range commitFiles {
-			item := FileItem{Path: f.Path, Status: f.Status}
+			item := FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity}
 			if stats != nil {
 				if s, ok := stats[f.Path]; ok {
 					item.Additions = s.Additions
@@ -794,12 +1913,47 @@ func (m *Model) loadFilesForCurrentCommit() tea.Msg {
 }
 
 func (m *Model) loadDiffForCurrentFile() tea.Msg {
+	if m.preview != nil {
+		return diffLoadedMsg{content: m.preview.Diffs[m.currentFile]}
+	}
+	if m.refDiff != nil && m.currentFile != "" {
+		diff, err := m.loadRefDiffFile()
+		if err != nil {
+			return ErrorMsg{Err: err}
+		}
+		return diffLoadedMsg{content: diff}
+	}
 	if m.currentFile == "" || m.commitIndex >= len(m.commits) {
 		return diffLoadedMsg{content: ""}
 	}
 
 	commit := m.commits[m.commitIndex]
-	diff, err := m.gitService.GetDiffAtCommit(m.currentFile, commit.Hash)
+	var diff string
+	var err error
+	switch {
+	case m.repoView == viewStaged:
+		diff, err = m.gitService.GetStagedDiff(m.currentFile)
+	case m.repoView == viewWorkingCopy:
+		if item := m.sidebar.SelectedItem(); item != nil && item.Path == m.currentFile && item.OldPath != "" {
+			diff, err = m.gitService.GetWorkingCopyRenameDiff(item.OldPath, item.Path)
+		} else {
+			diff, err = m.gitService.GetWorkingCopyDiff(m.currentFile)
+		}
+	case m.inPRDiff():
+		diff, err = m.gitService.GetPRFileDiff(m.prView.Base, m.prView.Branch, m.currentFile)
+	case m.repoView == viewReflog:
+		diff, err = m.gitService.GetReflogStepDiff(m.currentFile, commit.Ref)
+	case m.repoView == viewStashes:
+		diff, err = m.gitService.GetStashDiff(commit.Hash, m.currentFile, m.stashBase)
+	case m.mergeResolution && commit.Parents > 1:
+		return m.loadMergeResolution(commit.Hash)
+	default:
+		if item := m.sidebar.SelectedItem(); item != nil && item.Path == m.currentFile && item.OldPath != "" {
+			diff, err = m.gitService.GetRenameDiffAtCommit(item.OldPath, item.Path, commit.Hash)
+		} else {
+			diff, err = m.gitService.GetDiffAtCommit(m.currentFile, commit.Hash)
+		}
+	}
 
 	if err != nil {
 		return ErrorMsg{Err: err}
@@ -808,7 +1962,13 @@ func (m *Model) loadDiffForCurrentFile() tea.Msg {
 	if diff == "" {
 		return diffLoadedMsg{content: "No changes to display"}
 	}
+	if summary := m.summarizeSubmodule(m.currentFile, diff); summary != diff {
+		return diffLoadedMsg{content: summary}
+	}
 
+	if item := m.sidebar.SelectedItem(); item != nil && item.Path == m.currentFile && item.Status == "D" {
+		return diffLoadedMsg{content: diff, banner: deletedBanner(displayDiff)}
+	}
 	return diffLoadedMsg{content: diff}
 }
 
@@ -820,25 +1980,83 @@ func (m Model) View() string {
 	if m.err != nil {
 		return "Error: " + m.err.Error()
 	}
+	if m.commandLog != nil {
+		return m.commandLogView()
+	}
+	if m.keyHelp != nil {
+		return m.keyHelpView()
+	}
 
-	var help string
-	if m.textInputMode != "" {
-		badge := ModeBadgeFile.Render("FILE")
-		inputView := lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render("Search: ") + m.textInput.View()
-		help = badge + " " + inputView
+	// Mode badge, then where HEAD is and any state or message, then as much of the
+	// help as still fits
+	var badge, help string
+	if m.confirmingQuit {
+		help = StatusStyle.Render(quitPrompt)
+	} else if m.confirmingDiscard != "" {
+		help = StatusStyle.Render(fmt.Sprintf(discardPrompt, m.confirmingDiscard))
+	} else if m.resizing {
+		badge = ModeBadgeTree.Render("RESIZE")
+		help = HelpStyle.Render(m.resizeHelp())
+	} else if m.textInputMode != "" {
+		badge = ModeBadgeFile.Render("FILE")
+		prompt := "Search: "
+		if !m.singleFileMode {
+			badge = ModeBadgeCommits.Render("COMMITS")
+		}
+		switch m.textInputMode {
+		case "pr":
+			prompt = "Branch: "
+		case "fetch":
+			prompt = "Fetch: "
+		case "glob":
+			prompt = "Glob: "
+		case "compare":
+			prompt = "Compare: "
+		case "owner":
+			prompt = "Owner: "
+		case "tag":
+			prompt = "Tag: "
+		case "range":
+			prompt = "Blame lines: "
+		case "author":
+			prompt = "Author lines: "
+		case "refdiff":
+			prompt = "Diff against: "
+		}
+		help = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Render(prompt) + m.textInput.View()
 	} else if m.singleFileMode {
-		badge := ModeBadgeFile.Render("FILE")
-		helpText := HelpStyle.Render("[1/2/3: focus | c: view | r: reflog | s: search | d/u: scroll | n/N: hunks | [/]: history | z: info | q: back]")
-		help = badge + " " + helpText
+		badge = ModeBadgeFile.Render("FILE")
+		help = HelpStyle.Render(fileModeHelp)
 	} else if m.showFileTree {
-		badge := ModeBadgeTree.Render("TREE")
-		helpText := HelpStyle.Render("[j/k: nav | enter: open | h/l: collapse/expand | t/esc: close | q: quit]")
-		help = badge + " " + helpText
+		badge = ModeBadgeTree.Render("TREE")
+		help = HelpStyle.Render(treeHelp)
 	} else {
-		badge := ModeBadgeCommits.Render("COMMITS")
-		helpText := HelpStyle.Render("[1/2/3: focus | j/k: nav | space: file mode | t: tree | [/]: commits | /: filter | n/N: hunks | z: info | q: quit]")
-		help = badge + " " + helpText
+		badge = ModeBadgeCommits.Render("COMMITS")
+		help = HelpStyle.Render(commitModeHelp)
+	}
+	var status []string
+	if badge != "" {
+		status = append(status, badge)
+	}
+	if m.headLabel != "" {
+		status = append(status, SubtitleStyle.Render(m.headLabel))
+	}
+	if m.previewLocked {
+		status = append(status, SourceBadge.Render("LOCKED"))
+	}
+	if m.ownerFilter != "" && !m.singleFileMode {
+		status = append(status, SourceBadge.Render("OWNER: "+m.ownerFilter))
+	}
+	if m.pinnedFile != "" && !m.singleFileMode {
+		status = append(status, SourceBadge.Render("PIN: "+filepath.Base(m.pinnedFile)))
+	}
+	if m.operation != nil {
+		status = append(status, StatusStyle.Render(m.operationView()))
+	}
+	if m.statusMsg != "" {
+		status = append(status, StatusStyle.Render(m.statusMsg))
 	}
+	help = ansi.Truncate(strings.Join(append(status, help), " "), m.width, "…")
 
 	diffRendered := injectBorderLabel(m.diffView.View(), "3", m.focus == focusDiffView)
 
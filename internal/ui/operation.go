@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"var/internal/git"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// operation is a potentially slow load, shown with a spinner until it finishes or esc cancels it
+type operation struct {
+	id      int
+	label   string
+	started time.Time
+	content bool // Loads the diff view content, so any later content load supersedes it
+
+	// The operation's own git service, so cancelling it stops only its commands
+	gitService *git.Service
+}
+
+type operationDoneMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// trackOperation runs load as the current operation, on a copy of the model whose git
+// service is scoped to the operation. A newer operation supersedes it, and its result
+// is dropped if it was cancelled or superseded.
+func (m *Model) trackOperation(label string, load func(op *Model) tea.Msg) tea.Cmd {
+	m.operationID++
+	id := m.operationID
+	op := *m
+	op.gitService = m.gitService.Scoped()
+	m.operation = &operation{id: id, label: label, started: time.Now(), gitService: op.gitService}
+	return tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return operationDoneMsg{id: id, msg: load(&op)}
+	})
+}
+
+// finishOperation reports whether msg belongs to the current operation, ending it if so
+func (m *Model) finishOperation(msg operationDoneMsg) bool {
+	if m.operation == nil || m.operation.id != msg.id {
+		return false
+	}
+	m.operation = nil
+	return true
+}
+
+// cancelOperation abandons the current operation and kills the git processes running
+// for it, leaving other commands such as the HEAD poll running
+func (m *Model) cancelOperation() tea.Cmd {
+	label := m.operation.label
+	m.operation.gitService.Cancel()
+	m.operation = nil
+	return m.setStatus("Cancelled: " + label)
+}
+
+// updateSpinner advances the spinner while an operation is running
+func (m *Model) updateSpinner(msg spinner.TickMsg) tea.Cmd {
+	if m.operation == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return cmd
+}
+
+// operationView renders the spinner, label and elapsed time of the current operation
+func (m *Model) operationView() string {
+	elapsed := time.Since(m.operation.started).Truncate(time.Second)
+	return fmt.Sprintf("%s %s %s (esc to cancel)", m.spinner.View(), m.operation.label, elapsed)
+}
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type pagerFinishedMsg struct {
+	err error
+}
+
+// openInPager suspends the TUI and pipes the current raw diff into the
+// configured pager command, resuming once it exits
+func (m *Model) openInPager() tea.Cmd {
+	cmd := exec.Command("sh", "-c", m.config.PagerCommand())
+	cmd.Dir = m.gitService.RepoPath()
+	cmd.Stdin = strings.NewReader(m.diffView.RawContent())
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		return pagerFinishedMsg{err: err}
+	})
+}
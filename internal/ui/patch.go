@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"path/filepath"
+
+	"var/internal/config"
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// PatchModel shows the files of a patch read from a file or stdin, without a repository
+type PatchModel struct {
+	name     string // The patch's file name, shown in the diff header
+	files    []git.PatchFile
+	current  int
+	sidebar  Sidebar
+	diffView DiffView
+	focus    focus
+	width    int
+	height   int
+	ratio    float64 // Share of the width given to the file list
+}
+
+// NewPatchModel creates the review screen for a patch's content
+func NewPatchModel(name, content string, cfg config.Config) PatchModel {
+	files := git.ParsePatch(content)
+	items := make([]FileItem, len(files))
+	for i, f := range files {
+		items[i] = FileItem{
+			Path:       f.Path,
+			Status:     f.Status,
+			OldPath:    f.OldPath,
+			Similarity: f.Similarity,
+			Additions:  f.Additions,
+			Deletions:  f.Deletions,
+		}
+	}
+
+	sidebar := NewSidebar(items, 30, 20)
+	sidebar.SetTruncateMode(parseTruncateMode(cfg.PathTruncation))
+	sidebar.SetRevision(filepath.Base(name))
+	diffView := NewDiffView(80, 20)
+	diffView.SetGutterMode(parseGutterMode(cfg.Gutter))
+	diffView.SetJoinHunks(cfg.JoinNearbyHunks)
+	diffView.SetColors(parseDiffColors(cfg.DiffColors))
+
+	m := PatchModel{
+		name:     filepath.Base(name),
+		files:    files,
+		sidebar:  sidebar,
+		diffView: diffView,
+		ratio:    clampSidebarRatio(config.LoadState().SidebarRatio),
+	}
+	m.setFocus(focusFileList)
+	m.showFile(0)
+	return m
+}
+
+func (m PatchModel) Init() tea.Cmd {
+	return nil
+}
+
+// showFile shows the section of the file at index i
+func (m *PatchModel) showFile(i int) {
+	if i < 0 || i >= len(m.files) {
+		m.diffView.SetFileInfo("", -1, 0, "")
+		m.diffView.SetContent("No files in this patch")
+		return
+	}
+	m.current = i
+	f := m.files[i]
+	label := f.Path
+	if f.OldPath != "" {
+		label = f.OldPath + " → " + f.Path
+	}
+	m.diffView.SetFileInfo(label, i, len(m.files), m.name)
+	m.diffView.SetContent(f.Diff)
+}
+
+func (m *PatchModel) setFocus(f focus) {
+	m.focus = f
+	m.sidebar.SetFocused(f == focusFileList)
+	m.diffView.SetFocused(f == focusDiffView)
+}
+
+func (m PatchModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		sidebarWidth := int(float64(m.width) * m.ratio)
+		m.sidebar.SetSize(sidebarWidth, m.height-3)
+		m.diffView.SetSize(m.width-sidebarWidth-4, m.height-3)
+		return m, nil
+
+	case tea.KeyMsg:
+		if m.sidebar.IsFiltering() {
+			break
+		}
+		switch msg.String() {
+		case "ctrl+c", "q":
+			return m, tea.Quit
+		case "tab", "shift+tab":
+			if m.focus == focusFileList {
+				m.setFocus(focusDiffView)
+			} else {
+				m.setFocus(focusFileList)
+			}
+			return m, nil
+		case "1", "2":
+			m.setFocus(focusFileList)
+			return m, nil
+		case "3", "enter":
+			m.setFocus(focusDiffView)
+			return m, nil
+		case "#":
+			m.diffView.ToggleLineNumbers()
+			return m, nil
+		case "e":
+			m.diffView.ToggleLongLines()
+			return m, nil
+		case "E":
+			m.diffView.ToggleLineEndings()
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	if m.focus == focusFileList {
+		m.sidebar, cmd = m.sidebar.Update(msg)
+		if i := m.sidebar.SelectedIndex(); i != m.current {
+			m.showFile(i)
+		}
+	} else {
+		m.diffView, cmd = m.diffView.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m PatchModel) View() string {
+	if m.width == 0 {
+		return "Loading..."
+	}
+	badge := ModeBadgeFile.Render("PATCH")
+	help := badge + " " + HelpStyle.Render("[1/2/3: focus | j/k: nav | enter: diff | d/u: scroll | n/N: hunks | #: line numbers | e: long lines | E: line endings | q: quit]")
+
+	left := injectBorderLabel(m.sidebar.View(), "2", m.focus == focusFileList)
+	right := injectBorderLabel(m.diffView.View(), "3", m.focus == focusDiffView)
+	main := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
+	return lipgloss.JoinVertical(lipgloss.Left, main, help)
+}
@@ -0,0 +1,16 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// togglePin pins the current file so it stays selected while moving between commits
+func (m *Model) togglePin() tea.Cmd {
+	if m.pinnedFile != "" {
+		m.pinnedFile = ""
+		return m.setStatus("Unpinned file")
+	}
+	if m.currentFile == "" {
+		return nil
+	}
+	m.pinnedFile = m.currentFile
+	return m.setStatus("Pinned " + m.currentFile)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"fmt"
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type previewLoadedMsg struct {
+	preview *git.PickPreview
+	label   string
+	err     error
+}
+
+// loadPickPreview previews cherry-picking (or reverting) the selected commit onto HEAD
+func (m *Model) loadPickPreview(revert bool) tea.Cmd {
+	if m.commitIndex >= len(m.commits) {
+		return nil
+	}
+	hash := m.commits[m.commitIndex].Hash
+	return func() tea.Msg {
+		if revert {
+			preview, err := m.gitService.PreviewRevert(hash)
+			return previewLoadedMsg{preview: preview, label: "REVERT " + hash, err: err}
+		}
+		preview, err := m.gitService.PreviewCherryPick(hash)
+		return previewLoadedMsg{preview: preview, label: "PICK " + hash, err: err}
+	}
+}
+
+// applyPreview shows the previewed files in the sidebar
+func (m *Model) applyPreview(msg previewLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Preview failed: %v", msg.err))
+	}
+	if len(msg.preview.Files) == 0 {
+		return m.setStatus("Already applied: preview produces no changes")
+	}
+
+	m.preview = msg.preview
+	items := make([]FileItem, len(msg.preview.Files))
+	for i, f := range msg.preview.Files {
+		items[i] = FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity}
+	}
+	m.sidebar.SetItems(items)
+	m.sidebar.SetRevision(msg.label)
+	m.currentFile = items[0].Path
+	m.setFocus(focusFileList)
+
+	conflicts := 0
+	for _, f := range msg.preview.Files {
+		if f.Status == "U" {
+			conflicts++
+		}
+	}
+	var cmd tea.Cmd
+	if conflicts > 0 {
+		cmd = m.setStatus(fmt.Sprintf("%s: %d conflicting file(s)", msg.label, conflicts))
+	}
+	return tea.Batch(cmd, m.loadDiffForCurrentFile)
+}
+
+// exitPreview discards the preview and reloads the selected commit's files
+func (m *Model) exitPreview() tea.Cmd {
+	m.preview = nil
+	return m.loadFilesForCurrentCommit
+}
@@ -0,0 +1,106 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"var/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prAllChanges is the commit list entry showing the whole branch diff, listed above its commits
+const prAllChanges = "PR"
+
+type prViewLoadedMsg struct {
+	view *git.PRView
+	err  error
+}
+
+type prDivergenceMsg struct {
+	branch        string
+	ahead, behind int
+	err           error
+}
+
+// promptPRView asks for the branch to review, or leaves the PR view if it is active
+func (m *Model) promptPRView() tea.Cmd {
+	if m.repoView == viewPR {
+		return m.exitPRView()
+	}
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "branch or base...branch"
+	m.textInput.Focus()
+	m.textInputMode = "pr"
+	return textinput.Blink
+}
+
+// loadPRView resolves "branch" or "base...branch" and loads the branch's changes
+func (m *Model) loadPRView(spec string) tea.Cmd {
+	return func() tea.Msg {
+		base, branch, found := strings.Cut(spec, "...")
+		if !found {
+			branch = spec
+			defaultBranch, err := m.gitService.GetDefaultBranch()
+			if err != nil {
+				return prViewLoadedMsg{err: err}
+			}
+			base = defaultBranch
+		}
+		view, err := m.gitService.GetBranchPRView(strings.TrimSpace(base), strings.TrimSpace(branch))
+		return prViewLoadedMsg{view: view, err: err}
+	}
+}
+
+// applyPRView lists the branch diff entry followed by the branch's commits
+func (m *Model) applyPRView(msg prViewLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("PR view failed: %v", msg.err))
+	}
+	if len(msg.view.Commits) == 0 {
+		return m.setStatus(fmt.Sprintf("%s has no commits that aren't in %s", msg.view.Branch, msg.view.Base))
+	}
+	m.preview = nil
+	m.prView = msg.view
+	m.prDivergence = ""
+	m.repoView = viewPR
+	m.commitIndex = 0
+	all := git.Commit{Hash: prAllChanges, Message: fmt.Sprintf("All changes (%s...%s)", msg.view.Base, msg.view.Branch)}
+	m.commits = append([]git.Commit{all}, msg.view.Commits...)
+	m.populateCommitList(m.commits)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(0)
+	return tea.Batch(m.loadFilesForCurrentCommit, m.loadPRDivergence(msg.view.Branch, msg.view.Base))
+}
+
+// loadPRDivergence counts the branch's commits ahead of and behind its base in the background
+func (m *Model) loadPRDivergence(branch, base string) tea.Cmd {
+	return func() tea.Msg {
+		ahead, behind, err := m.gitService.GetBranchDivergence(branch, base)
+		return prDivergenceMsg{branch: branch, ahead: ahead, behind: behind, err: err}
+	}
+}
+
+// applyPRDivergence adds ↑ahead ↓behind to the PR view title, unless the view moved on
+func (m *Model) applyPRDivergence(msg prDivergenceMsg) {
+	if msg.err != nil || m.repoView != viewPR || m.prView == nil || m.prView.Branch != msg.branch {
+		return
+	}
+	m.prDivergence = fmt.Sprintf("↑%d ↓%d", msg.ahead, msg.behind)
+	m.commitList.SetTitle(m.commitListTitle())
+}
+
+// exitPRView returns the commit list to recent commits
+func (m *Model) exitPRView() tea.Cmd {
+	m.prView = nil
+	m.repoView = viewCommits
+	m.commitIndex = 0
+	m.commitList.SetTitle(m.commitListTitle())
+	return m.loadInitialData
+}
+
+// inPRDiff reports whether the selected entry is the whole-branch diff
+func (m *Model) inPRDiff() bool {
+	return m.repoView == viewPR && m.prView != nil && m.commitIndex == 0
+}
@@ -0,0 +1,24 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+const quitPrompt = "You have staged changes — quit anyway? (y/n)"
+
+// quit exits, first asking for confirmation if enabled and this session changed the index
+func (m *Model) quit() tea.Cmd {
+	if m.config.ConfirmQuitAfterStaging && m.dirtiedIndex {
+		m.confirmingQuit = true
+		return nil
+	}
+	return tea.Quit
+}
+
+// answerQuitPrompt quits on y (or a repeated quit key) and cancels on anything else
+func (m *Model) answerQuitPrompt(msg tea.KeyMsg) tea.Cmd {
+	m.confirmingQuit = false
+	switch msg.String() {
+	case "y", "Y", "q", "ctrl+c":
+		return tea.Quit
+	}
+	return nil
+}
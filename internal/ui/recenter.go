@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// zTimeout is how long z waits for a second key before toggling the description
+const zTimeout = 500 * time.Millisecond
+
+type zTimeoutMsg struct {
+	id int
+}
+
+// listWindow lets a list scroll line by line from a chosen first row instead of paging,
+// so zz/zt/zb can put the selection in the middle, at the top or at the bottom
+type listWindow struct {
+	top    int  // Row shown first
+	active bool // Off until the selection is placed, leaving the list's own paging
+}
+
+// place scrolls so the selection sits at where: "center", "top" or "bottom"
+func (w *listWindow) place(l *list.Model, where string) {
+	if l.FilterState() != list.Unfiltered {
+		return
+	}
+	idx, rows := l.Index(), l.Paginator.PerPage
+	switch where {
+	case "top":
+		w.top = idx
+	case "bottom":
+		w.top = idx - rows + 1
+	default:
+		w.top = idx - rows/2
+	}
+	w.top = min(max(w.top, 0), max(len(l.Items())-rows, 0))
+	w.active = true
+}
+
+// follow scrolls just enough to keep the selection in view after it moves
+func (w *listWindow) follow(l *list.Model) {
+	w.top = w.first(*l)
+}
+
+// reset returns to the list's paging, for new items
+func (w *listWindow) reset() {
+	w.active = false
+	w.top = 0
+}
+
+// first returns the first row to show with the selection in view
+func (w *listWindow) first(l list.Model) int {
+	idx, rows := l.Index(), max(l.Paginator.PerPage, 1)
+	top := w.top
+	if idx < top {
+		top = idx
+	} else if idx >= top+rows {
+		top = idx - rows + 1
+	}
+	return max(top, 0)
+}
+
+// view renders the list from the window's first row. The list only draws whole pages,
+// so a copy holding the rows from there on draws them as its first page, without the
+// page dots, which don't apply to a scrolled list.
+func (w *listWindow) view(l list.Model) string {
+	if !w.active || l.FilterState() != list.Unfiltered {
+		return l.View()
+	}
+	top := w.first(l)
+	if top == 0 {
+		return l.View()
+	}
+	idx := l.Index()
+	l.SetItems(l.Items()[top:])
+	l.SetShowPagination(false)
+	l.SetHeight(l.Height() - 1)
+	l.Select(idx - top)
+	return l.View()
+}
+
+// startZ waits for the key after z, which picks a z command; without one, z toggles
+// the commit description once zTimeout passes
+func (m *Model) startZ() tea.Cmd {
+	m.zPending = true
+	m.zID++
+	id := m.zID
+	return tea.Tick(zTimeout, func(time.Time) tea.Msg {
+		return zTimeoutMsg{id: id}
+	})
+}
+
+// toggleDescription shows or hides the commit description, loading the tag notes and
+// git note it shows
+func (m *Model) toggleDescription() tea.Cmd {
+	m.diffView.ToggleDescription()
+	return tea.Batch(m.loadTagNotes(), m.loadCommitNote())
+}
+
+// recenterKeys maps the key after z to where the selection is placed
+var recenterKeys = map[string]string{"z": "center", "t": "top", "b": "bottom"}
+
+// recenter places the selection of the focused list
+func (m *Model) recenter(where string) {
+	switch m.focus {
+	case focusCommitList:
+		m.commitList.Recenter(where)
+	case focusFileList:
+		m.sidebar.Recenter(where)
+	}
+}
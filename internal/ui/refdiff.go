@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+
+	"var/internal/git"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// refDiff is a comparison of the selected commit with a typed ref, listed in the file list
+type refDiff struct {
+	from, to string // Commits compared
+	label    string // "from..ref" as typed, for the headers
+}
+
+type refDiffLoadedMsg struct {
+	diff  *refDiff
+	files []git.FileStatus
+	err   error
+}
+
+// promptRefDiff asks for a ref to diff the selected commit against
+func (m *Model) promptRefDiff() tea.Cmd {
+	if m.repoView != viewCommits || m.commitIndex >= len(m.commits) {
+		return nil
+	}
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "main, v1.2.0, HEAD~3"
+	m.textInput.Focus()
+	m.textInputMode = "refdiff"
+	return textinput.Blink
+}
+
+// loadRefDiff resolves the ref and lists the files that differ from the selected commit to it
+func (m *Model) loadRefDiff(ref string) tea.Cmd {
+	if m.commitIndex >= len(m.commits) {
+		return nil
+	}
+	from := m.commits[m.commitIndex].Hash
+	return func() tea.Msg {
+		to, err := m.gitService.ResolveRef(ref)
+		if err != nil {
+			return refDiffLoadedMsg{err: err}
+		}
+		files, err := m.gitService.GetFilesBetweenCommits(from, to)
+		diff := &refDiff{from: from, to: to, label: from + ".." + ref}
+		return refDiffLoadedMsg{diff: diff, files: files, err: err}
+	}
+}
+
+// applyRefDiff shows the compared files in the file list until the selection moves or esc
+func (m *Model) applyRefDiff(msg refDiffLoadedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Diff failed: %v", msg.err))
+	}
+	if len(msg.files) == 0 {
+		return m.setStatus(fmt.Sprintf("No differences in %s", msg.diff.label))
+	}
+
+	m.preview = nil
+	m.refDiff = msg.diff
+	items := make([]FileItem, len(msg.files))
+	for i, f := range msg.files {
+		items[i] = FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity}
+	}
+	m.sidebar.SetItems(items)
+	m.currentFile = items[0].Path
+	m.updateRevisionDisplay()
+	m.setFocus(focusFileList)
+	return m.loadDiffForCurrentFile
+}
+
+// exitRefDiff drops the comparison and reloads the selected commit's files
+func (m *Model) exitRefDiff() tea.Cmd {
+	m.refDiff = nil
+	return m.loadFilesForCurrentCommit
+}
+
+// loadRefDiffFile diffs the current file across the comparison
+func (m *Model) loadRefDiffFile() (string, error) {
+	if item := m.sidebar.SelectedItem(); item != nil && item.Path == m.currentFile && item.OldPath != "" {
+		return m.gitService.GetRenameDiffBetweenCommits(item.OldPath, item.Path, m.refDiff.from, m.refDiff.to)
+	}
+	return m.gitService.GetDiffBetweenCommits(m.currentFile, m.refDiff.from, m.refDiff.to)
+}
@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"time"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// headPollInterval is how often HEAD is checked for commits made outside var
+const headPollInterval = 2 * time.Second
+
+type headCheckedMsg struct {
+	head   string
+	branch string // Empty when HEAD is detached
+}
+
+type commitsRefreshedMsg struct {
+	commits []git.Commit
+	rev     string
+	head    string
+}
+
+// pollHead checks HEAD after the poll interval, or never if auto-refresh is disabled
+func (m *Model) pollHead() tea.Cmd {
+	if m.config.DisableAutoRefresh {
+		return nil
+	}
+	// Every tick runs two commands, which would soon crowd the command log
+	poll := m.gitService.Unlogged()
+	return tea.Tick(headPollInterval, func(time.Time) tea.Msg {
+		head, _ := poll.GetHead()
+		branch, _ := poll.GetHeadBranch()
+		return headCheckedMsg{head: head, branch: branch}
+	})
+}
+
+// headLabel describes where HEAD is: the branch name, or the commit when detached
+func headLabel(head, branch string) string {
+	if branch != "" {
+		return branch
+	}
+	if len(head) > 7 {
+		head = head[:7]
+	}
+	return "(detached at " + head + ")"
+}
+
+// applyHeadChecked reloads the commits when HEAD has moved since the last check
+func (m *Model) applyHeadChecked(msg headCheckedMsg) tea.Cmd {
+	if msg.head != "" {
+		m.headLabel = headLabel(msg.head, msg.branch)
+	}
+	if msg.head == "" || msg.head == m.lastHead {
+		return m.pollHead()
+	}
+	if m.lastHead == "" {
+		// First check: nothing to compare against yet
+		m.lastHead = msg.head
+		return m.pollHead()
+	}
+	m.lastHead = msg.head
+	head := msg.head
+	rev := m.commitLogRev()
+	if m.commitCounts != nil {
+		m.resetCommitCounts()
+	}
+	m.resetLastChanges()
+	return tea.Batch(m.pollHead(), m.loadCommitCounts(), func() tea.Msg {
+		commits, _ := m.gitService.GetCommitsFrom(rev, commitPageSize)
+		return commitsRefreshedMsg{commits: commits, rev: rev, head: head}
+	})
+}
+
+// applyCommitsRefreshed swaps in the reloaded commits, keeping the selected commit if it still exists
+func (m *Model) applyCommitsRefreshed(msg commitsRefreshedMsg) tea.Cmd {
+	short := msg.head
+	if len(short) > 7 {
+		short = short[:7]
+	}
+	status := m.setStatus("HEAD moved to " + short)
+	if m.repoView != viewCommits || m.logStart != "" || msg.rev != m.commitLogRev() {
+		// The commit list is showing something else; it reloads when returning to commits
+		return status
+	}
+
+	var selected string
+	if m.commitIndex < len(m.commits) {
+		selected = m.commits[m.commitIndex].Hash
+	}
+	m.allCommits = msg.commits
+	m.moreCommits = len(msg.commits) == commitPageSize
+	m.commits = m.filterByType(msg.commits)
+
+	m.commitIndex = -1
+	for i, c := range m.commits {
+		if c.Hash == selected {
+			m.commitIndex = i
+			break
+		}
+	}
+	reload := m.commitIndex < 0
+	if reload {
+		m.commitIndex = 0
+	}
+
+	if m.singleFileMode {
+		// The list shows the file's history; the new commits appear on exit
+		return status
+	}
+	m.populateCommitList(m.commits)
+	m.commitList.SelectIndex(m.commitIndex)
+	m.updateRevisionDisplay()
+	if reload {
+		return tea.Batch(status, m.loadFilesForCurrentCommit)
+	}
+	return status
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type refsLoadedMsg struct {
+	refs []git.Ref
+	err  error
+}
+
+// toggleRefs switches the commit list between the commits and a palette of every branch
+// and tag, where enter shows the commits from the selected ref
+func (m *Model) toggleRefs() tea.Cmd {
+	m.preview = nil
+	m.commitIndex = 0
+	if m.repoView == viewRefs {
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadInitialData
+	}
+	m.repoView = viewRefs
+	return m.loadRefs
+}
+
+func (m *Model) loadRefs() tea.Msg {
+	refs, err := m.gitService.GetRefs()
+	if err != nil {
+		return refsLoadedMsg{err: err}
+	}
+	// HEAD comes first, to go back to the current history
+	if head, err := m.gitService.GetHead(); err == nil {
+		refs = append([]git.Ref{{Name: "HEAD", Kind: "head", Hash: head}}, refs...)
+	}
+	return refsLoadedMsg{refs: refs}
+}
+
+// applyRefs lists the refs as entries of the commit list, each showing its commit's files
+func (m *Model) applyRefs(msg refsLoadedMsg) tea.Cmd {
+	if msg.err != nil || len(msg.refs) == 0 {
+		// Fall back to the commit list, which may have been replaced by another view
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		status := "No branches or tags"
+		if msg.err != nil {
+			status = "Failed to list refs: " + msg.err.Error()
+		}
+		return tea.Batch(m.setStatus(status), m.loadInitialData)
+	}
+	m.refs = msg.refs
+	m.commits = make([]git.Commit, len(msg.refs))
+	for i, r := range msg.refs {
+		message := r.Kind + " " + r.Name
+		if div := r.Divergence(); div != "" {
+			message += " " + div
+		}
+		m.commits[i] = git.Commit{Hash: r.Hash, Message: message, Parents: r.Parents}
+	}
+	m.populateCommitList(m.commits)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(m.commitIndex)
+	return m.loadFilesForCurrentCommit
+}
+
+// gotoSelectedRef shows the commit history starting at the selected ref
+func (m *Model) gotoSelectedRef() tea.Cmd {
+	if m.commitIndex >= len(m.refs) {
+		return nil
+	}
+	ref := m.refs[m.commitIndex]
+	if ref.Kind == "head" {
+		return m.showHistoryFrom("")
+	}
+	return m.showHistoryFrom(ref.Name)
+}
+
+// showHistoryFrom lists the commits from a ref, or from HEAD when it is empty
+func (m *Model) showHistoryFrom(ref string) tea.Cmd {
+	m.pushHistory()
+	m.allRefs = false
+	m.logStart = ref
+	m.repoView = viewCommits
+	m.commitIndex = 0
+	m.commitList.SetTitle(m.commitListTitle())
+	return m.loadInitialData
+}
@@ -0,0 +1,18 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// reloadDiff loads the current file's diff again, for one gone stale after the file was
+// edited outside var, without reloading the commits or file list. The selection and
+// scroll position are kept.
+func (m *Model) reloadDiff() tea.Cmd {
+	if m.currentFile == "" {
+		return m.setStatus("No file to reload")
+	}
+	m.pendingOffset = m.diffView.YOffset()
+	status := m.setStatus("Reloaded " + m.currentFile)
+	if m.singleFileMode {
+		return tea.Batch(status, m.loadContentForCurrentSource())
+	}
+	return tea.Batch(status, m.loadDiffForCurrentFile)
+}
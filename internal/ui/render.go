@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Diffs longer than incrementalRenderLines show their first incrementalRenderHead lines
+// rendered right away, with the rest as git colored them until the full render finishes
+// in the background
+const (
+	incrementalRenderLines = 2000
+	incrementalRenderHead  = 300
+)
+
+// pendingRender is a full render waiting to be started in the background
+type pendingRender struct {
+	gen     int
+	content string
+	gutter  GutterMode
+	colors  diffColorScheme
+	width   int
+}
+
+// diffRenderedMsg carries a background render of the content of generation gen
+type diffRenderedMsg struct {
+	gen      int
+	rendered string
+}
+
+// renderIncrementally shows a long diff at once, rendering the first screens now and
+// queueing the full render, and reports false for diffs short enough to render directly
+func (d *DiffView) renderIncrementally(content string, gutter GutterMode) bool {
+	lines := strings.Split(content, "\n")
+	if len(lines) <= incrementalRenderLines {
+		return false
+	}
+
+	head, _ := addLineNumbers(strings.Join(lines[:incrementalRenderHead], "\n"), gutter, d.colors, d.viewport.Width)
+	// Rendering keeps one line per input line, so the hunk headers are where they will end up
+	d.hunkPositions = nil
+	for i, line := range lines {
+		if hunkHeaderRegex.MatchString(stripANSI(line)) {
+			d.hunkPositions = append(d.hunkPositions, i)
+		}
+	}
+	d.pendingRender = &pendingRender{gen: d.renderGen, content: content, gutter: gutter, colors: d.colors, width: d.viewport.Width}
+	d.setViewportContent(head + "\n" + strings.Join(lines[incrementalRenderHead:], "\n"))
+	return true
+}
+
+// takePendingRender returns a command running the queued full render and delta run, if any
+func (d *DiffView) takePendingRender() tea.Cmd {
+	var cmds []tea.Cmd
+	if p := d.pendingRender; p != nil {
+		d.pendingRender = nil
+		cmds = append(cmds, func() tea.Msg {
+			rendered, _ := addLineNumbers(p.content, p.gutter, p.colors, p.width)
+			return diffRenderedMsg{gen: p.gen, rendered: rendered}
+		})
+	}
+	if p := d.pendingDelta; p != nil {
+		d.pendingDelta = nil
+		cmds = append(cmds, func() tea.Msg {
+			return deltaRenderedMsg{gen: p.gen, render: runDelta(p.input, p.width)}
+		})
+	}
+	return tea.Batch(cmds...)
+}
+
+// applyRender swaps in a finished background render unless the content changed since
+func (d *DiffView) applyRender(msg diffRenderedMsg) {
+	if msg.gen != d.renderGen {
+		return
+	}
+	d.setViewportContent(msg.rendered)
+}
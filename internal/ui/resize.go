@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+
+	"var/internal/config"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultSidebarRatio = 0.20
+	minSidebarRatio     = 0.10
+	maxSidebarRatio     = 0.60
+	sidebarRatioStep    = 0.02
+)
+
+type sidebarRatioSavedMsg struct {
+	err error
+}
+
+// clampSidebarRatio keeps a ratio within the usable range, defaulting when unset
+func clampSidebarRatio(ratio float64) float64 {
+	if ratio == 0 {
+		return defaultSidebarRatio
+	}
+	// Whole percents, so repeated steps don't drift
+	return min(max(math.Round(ratio*100)/100, minSidebarRatio), maxSidebarRatio)
+}
+
+// startResize enters resize mode, where the arrow keys move the split between the
+// left column and the diff
+func (m *Model) startResize() {
+	m.resizing = true
+	m.resizeFrom = m.sidebarRatio
+}
+
+// answerResize adjusts the split live, keeping it on enter and restoring it on esc
+func (m *Model) answerResize(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "left", "h", "<":
+		m.sidebarRatio = clampSidebarRatio(m.sidebarRatio - sidebarRatioStep)
+	case "right", "l", ">":
+		m.sidebarRatio = clampSidebarRatio(m.sidebarRatio + sidebarRatioStep)
+	case "enter":
+		m.resizing = false
+		ratio := m.sidebarRatio
+		return func() tea.Msg {
+			state := config.LoadState()
+			state.SidebarRatio = ratio
+			return sidebarRatioSavedMsg{err: config.SaveState(state)}
+		}
+	case "esc", "q", "ctrl+c":
+		m.resizing = false
+		m.sidebarRatio = m.resizeFrom
+	default:
+		return nil
+	}
+	m.updateLayout()
+	return nil
+}
+
+// resizeHelp describes resize mode in the help bar
+func (m *Model) resizeHelp() string {
+	return fmt.Sprintf("[←/→: resize (%d%%) | enter: keep | esc: cancel]", int(m.sidebarRatio*100+0.5))
+}
+
+func (m *Model) applySidebarRatioSaved(msg sidebarRatioSavedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Failed to save width: %v", msg.err))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// showCommandMsg carries a git show command reproducing the view, built once the
+// commit's full hash is resolved
+type showCommandMsg struct {
+	command string
+	err     error
+}
+
+// shellSafe matches words that need no quoting in a shell
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]+$`)
+
+// shellQuote quotes word for a POSIX shell if it needs it
+func shellQuote(word string) string {
+	if shellSafe.MatchString(word) {
+		return word
+	}
+	return "'" + strings.ReplaceAll(word, "'", `'\''`) + "'"
+}
+
+// viewedCommit returns the commit shown in the diff view and, unless the whole commit
+// is in view, the paths of the file shown (its old path first for a rename). Views of
+// something other than a commit's own changes, such as a reflog step or the working
+// copy, have none.
+func (m *Model) viewedCommit() (string, []string, bool) {
+	if m.singleFileMode {
+		if _, stash := m.currentFileStash(); stash || m.sourceMode == sourceReflog {
+			return "", nil, false
+		}
+		hash, ok := m.currentCommitForSource()
+		return hash, []string{m.currentFile}, ok && m.currentFile != ""
+	}
+	if m.preview != nil || m.refDiff != nil || m.commitIndex >= len(m.commits) || m.inPRDiff() ||
+		m.repoView == viewStaged || m.repoView == viewWorkingCopy ||
+		m.repoView == viewStashes || m.repoView == viewReflog {
+		return "", nil, false
+	}
+	hash := m.commits[m.commitIndex].Hash
+	if m.focus == focusCommitList || m.currentFile == "" {
+		return hash, nil, true
+	}
+	if item := m.sidebar.SelectedItem(); item != nil && item.Path == m.currentFile && item.OldPath != "" {
+		return hash, []string{item.OldPath, item.Path}, true
+	}
+	return hash, []string{m.currentFile}, true
+}
+
+// copyShowCommand copies a git show command that reproduces what the diff view shows:
+// the commit by its full hash, limited to the file being viewed, with the view's context
+// and textconv settings
+func (m *Model) copyShowCommand() tea.Cmd {
+	hash, paths, ok := m.viewedCommit()
+	if !ok {
+		return m.setStatus("Not viewing a commit")
+	}
+	var opts, textconv []string
+	if !m.gitService.Textconv() {
+		textconv = []string{"--no-textconv"}
+	}
+	if m.singleFileMode && m.displayMode == displayContext {
+		opts = append(opts, "-U10")
+		if m.config.JoinNearbyHunks {
+			opts = append(opts, "--inter-hunk-context=10")
+		}
+	} else if m.config.JoinNearbyHunks {
+		opts = append(opts, "--inter-hunk-context=3")
+	}
+	full := m.singleFileMode && m.displayMode == displayFull
+	return func() tea.Msg {
+		rev, err := m.gitService.FullHash(hash)
+		if err != nil {
+			return showCommandMsg{err: err}
+		}
+		if full {
+			// Full mode shows the file itself rather than the commit's changes to it
+			words := append(append([]string{"git", "show"}, textconv...), shellQuote(rev+":"+paths[0]))
+			return showCommandMsg{command: strings.Join(words, " ")}
+		}
+		words := append(append([]string{"git", "show"}, opts...), textconv...)
+		words = append(words, rev)
+		if len(paths) > 0 {
+			words = append(words, "--")
+			for _, path := range paths {
+				words = append(words, shellQuote(path))
+			}
+		}
+		return showCommandMsg{command: strings.Join(words, " ")}
+	}
+}
+
+func (m *Model) applyShowCommand(msg showCommandMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Copy failed: %v", msg.err))
+	}
+	return m.copyToClipboard(msg.command, msg.command)
+}
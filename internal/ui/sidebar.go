@@ -3,37 +3,36 @@ package ui
 import (
 	"fmt"
 	"io"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // FileItem represents a file in the sidebar
 type FileItem struct {
-	Path      string
-	Status    string
-	Additions int
-	Deletions int
+	Path       string
+	Status     string
+	OldPath    string // Original path of a rename or copy
+	Similarity int    // Similarity percentage of a rename or copy
+	Additions  int
+	Deletions  int
+	Owners     string // CODEOWNERS owners, space separated
 }
 
 func (i FileItem) FilterValue() string { return i.Path }
 
-type fileItemDelegate struct{}
+type fileItemDelegate struct {
+	truncate   TruncateMode
+	showOwners bool           // List CODEOWNERS owners after the stats
+	commits    map[string]int // Commits touching each file, shown after the stats when set
+}
 
 func (d fileItemDelegate) Height() int                             { return 1 }
 func (d fileItemDelegate) Spacing() int                            { return 0 }
 func (d fileItemDelegate) Update(_ tea.Msg, _ *list.Model) tea.Cmd { return nil }
-// truncatePath shortens a path to fit within maxLen, showing start and end
-func truncatePath(path string, maxLen int) string {
-	if len(path) <= maxLen || maxLen <= 5 {
-		return path
-	}
-	// Show first 3 chars + … + end
-	endLen := maxLen - 4 // 3 for start + 1 for …
-	return path[:3] + "…" + path[len(path)-endLen:]
-}
-
 func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem list.Item) {
 	i, ok := listItem.(FileItem)
 	if !ok {
@@ -48,14 +47,25 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	if i.Additions > 0 || i.Deletions > 0 {
 		stats = fmt.Sprintf("+%d -%d", i.Additions, i.Deletions)
 	}
+	var extra string
+	if n, ok := d.commits[i.Path]; ok && n >= 0 {
+		extra = fmt.Sprintf(" (%d)", n)
+	}
+	if d.showOwners && i.Owners != "" {
+		extra += " " + i.Owners
+	}
 
-	// Truncate path to fit: width - 2 (indent) - 3 (status) - 1 (space) - 2 (margin) - stats - 1 (space before stats)
+	// Truncate path to fit: width - 2 (indent) - 3 (status) - 1 (space) - 2 (margin) - stats - 1 (space before stats) - extra
 	statsWidth := 0
 	if stats != "" {
 		statsWidth = len(stats) + 1
 	}
-	maxPathLen := width - 8 - statsWidth
-	path := truncatePath(i.Path, maxPathLen)
+	maxPathLen := width - 8 - statsWidth - utf8.RuneCountInString(extra)
+	label := i.Path
+	if i.OldPath != "" {
+		label = i.OldPath + " → " + i.Path
+	}
+	path := truncatePath(label, maxPathLen, d.truncate)
 
 	// Determine status color
 	var statusColor lipgloss.Color
@@ -66,6 +76,10 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		statusColor = lipgloss.Color("2") // Green
 	case "D":
 		statusColor = lipgloss.Color("1") // Red
+	case "U":
+		statusColor = lipgloss.Color("5") // Magenta for conflicts
+	case "R", "C":
+		statusColor = lipgloss.Color("6") // Cyan for renames and copies
 	default:
 		statusColor = lipgloss.Color("7") // White/default
 	}
@@ -81,12 +95,17 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 		pathRendered := pathStyle.Render(path)
 		if stats != "" {
 			// Pad path to push stats to the right
-			padLen := maxPathLen - len(path)
+			padLen := maxPathLen - utf8.RuneCountInString(path)
 			if padLen < 0 {
 				padLen = 0
 			}
 			padding := lipgloss.NewStyle().Background(bg).Render(fmt.Sprintf("%*s", padLen, ""))
-			line := fmt.Sprintf("  %s %s%s %s", statusStyle.Render(i.Status), pathRendered, padding, statsStyle.Render(stats))
+			line := fmt.Sprintf("  %s %s%s %s", statusStyle.Render(i.Status), pathRendered, padding, statsStyle.Render(stats+extra))
+			fmt.Fprint(w, lipgloss.NewStyle().Width(width).Background(bg).Render(line))
+		} else if extra != "" {
+			padLen := max(maxPathLen-utf8.RuneCountInString(path), 0)
+			padding := lipgloss.NewStyle().Background(bg).Render(fmt.Sprintf("%*s", padLen, ""))
+			line := fmt.Sprintf("  %s %s%s%s", statusStyle.Render(i.Status), pathRendered, padding, statsStyle.Render(extra))
 			fmt.Fprint(w, lipgloss.NewStyle().Width(width).Background(bg).Render(line))
 		} else {
 			line := fmt.Sprintf("  %s %s", statusStyle.Render(i.Status), pathRendered)
@@ -95,8 +114,9 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 	} else {
 		// Unselected: normal styling
 		statusStyle := lipgloss.NewStyle().Width(3).Foreground(statusColor)
+		extraStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
 		if stats != "" {
-			padLen := maxPathLen - len(path)
+			padLen := maxPathLen - utf8.RuneCountInString(path)
 			if padLen < 0 {
 				padLen = 0
 			}
@@ -104,7 +124,11 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 			delStr := fmt.Sprintf("-%d", i.Deletions)
 			greenStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
 			redStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
-			line := fmt.Sprintf("  %s %s%*s %s %s", statusStyle.Render(i.Status), path, padLen, "", greenStyle.Render(addStr), redStyle.Render(delStr))
+			line := fmt.Sprintf("  %s %s%*s %s %s%s", statusStyle.Render(i.Status), path, padLen, "", greenStyle.Render(addStr), redStyle.Render(delStr), extraStyle.Render(extra))
+			fmt.Fprint(w, line)
+		} else if extra != "" {
+			padLen := max(maxPathLen-utf8.RuneCountInString(path), 0)
+			line := fmt.Sprintf("  %s %s%*s%s", statusStyle.Render(i.Status), path, padLen, "", extraStyle.Render(extra))
 			fmt.Fprint(w, line)
 		} else {
 			line := fmt.Sprintf("  %s %s", statusStyle.Render(i.Status), path)
@@ -116,10 +140,15 @@ func (d fileItemDelegate) Render(w io.Writer, m list.Model, index int, listItem
 // Sidebar wraps a bubbles/list for file selection
 type Sidebar struct {
 	list      list.Model
+	delegate  fileItemDelegate
 	width     int
 	height    int
 	isFocused bool
 	revision  string // "working copy" or commit hash
+	footer    string // Line pinned below the list (empty for none)
+	status    string // Name of the status the list is limited to (empty for all)
+
+	window listWindow // Scrolling set by zz/zt/zb
 }
 
 func NewSidebar(items []FileItem, width, height int) Sidebar {
@@ -145,18 +174,69 @@ func NewSidebar(items []FileItem, width, height int) Sidebar {
 	}
 }
 
+// SetTruncateMode sets how paths too long for the list are shortened
+func (s *Sidebar) SetTruncateMode(mode TruncateMode) {
+	s.delegate.truncate = mode
+	s.list.SetDelegate(s.delegate)
+}
+
+// SetShowOwners sets whether files are listed with their CODEOWNERS owners
+func (s *Sidebar) SetShowOwners(show bool) {
+	s.delegate.showOwners = show
+	s.list.SetDelegate(s.delegate)
+}
+
+// SetCommitCounts shows each file's count from counts after its stats, or hides them when nil.
+// Files missing from counts, or with a negative count, are shown without one
+func (s *Sidebar) SetCommitCounts(counts map[string]int) {
+	s.delegate.commits = counts
+	s.list.SetDelegate(s.delegate)
+}
+
+// VisiblePaths returns the paths of the files on the list's current page
+func (s Sidebar) VisiblePaths() []string {
+	items := s.list.VisibleItems()
+	start, end := s.list.Paginator.GetSliceBounds(len(items))
+	var paths []string
+	for _, item := range items[start:end] {
+		if f, ok := item.(FileItem); ok {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths
+}
+
 func (s *Sidebar) SetItems(items []FileItem) {
 	listItems := make([]list.Item, len(items))
 	for i, item := range items {
 		listItems[i] = item
 	}
 	s.list.SetItems(listItems)
+	s.window.reset()
 }
 
 func (s *Sidebar) SetSize(width, height int) {
 	s.width = width
 	s.height = height
-	s.list.SetSize(width, height)
+	s.layoutList()
+}
+
+// layoutList sizes the list to the space left by the footer
+func (s *Sidebar) layoutList() {
+	height := s.height
+	if s.footer != "" {
+		height--
+	}
+	s.list.SetSize(s.width, height)
+}
+
+// SetFooter pins a line below the list; an empty string removes it
+func (s *Sidebar) SetFooter(footer string) {
+	if footer == s.footer {
+		return
+	}
+	s.footer = footer
+	s.layoutList()
 }
 
 func (s *Sidebar) SetFocused(focused bool) {
@@ -169,10 +249,23 @@ func (s *Sidebar) IsFocused() bool {
 
 func (s *Sidebar) SetRevision(revision string) {
 	s.revision = revision
-	if revision == "" || revision == "working copy" {
+	s.updateTitle()
+}
+
+// SetStatusFilter names the status the list is limited to in its title, or clears it when empty
+func (s *Sidebar) SetStatusFilter(name string) {
+	s.status = name
+	s.updateTitle()
+}
+
+func (s *Sidebar) updateTitle() {
+	if s.revision == "" || s.revision == "working copy" {
 		s.list.Title = "Files (working copy)"
 	} else {
-		s.list.Title = fmt.Sprintf("Files (%s)", revision)
+		s.list.Title = fmt.Sprintf("Files (%s)", s.revision)
+	}
+	if s.status != "" {
+		s.list.Title += " [" + s.status + "]"
 	}
 }
 
@@ -189,12 +282,37 @@ func (s *Sidebar) SelectedItem() *FileItem {
 	return &fi
 }
 
+// SelectedIndex returns the selected item's position among all items, filtered out or not
+func (s *Sidebar) SelectedIndex() int {
+	return s.list.GlobalIndex()
+}
+
+// SelectPath selects the item with the given path, reporting whether it was found
+func (s *Sidebar) SelectPath(path string) bool {
+	for i, item := range s.list.Items() {
+		if fi, ok := item.(FileItem); ok && fi.Path == path {
+			s.list.Select(i)
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Sidebar) Update(msg tea.Msg) (Sidebar, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && !s.list.SettingFilter() && halfPage(&s.list, keyMsg.String()) {
+		return *s, nil
+	}
 	var cmd tea.Cmd
 	s.list, cmd = s.list.Update(msg)
+	s.window.follow(&s.list)
 	return *s, cmd
 }
 
+// Recenter scrolls the list so the selection sits at where: "center", "top" or "bottom"
+func (s *Sidebar) Recenter(where string) {
+	s.window.place(&s.list, where)
+}
+
 func (s *Sidebar) View() string {
 	style := lipgloss.NewStyle().
 		Width(s.width).
@@ -207,5 +325,10 @@ func (s *Sidebar) View() string {
 	}
 	// inactive: no BorderForeground = terminal default
 
-	return style.Render(s.list.View())
+	if s.footer == "" {
+		return style.Render(s.window.view(s.list))
+	}
+	body := lipgloss.NewStyle().Height(s.list.Height()).Render(s.window.view(s.list))
+	footer := SubtitleStyle.Padding(0, 1).Render(ansi.Truncate(s.footer, max(s.width-2, 1), "…"))
+	return style.Render(lipgloss.JoinVertical(lipgloss.Left, body, footer))
 }
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// sideBySide holds two texts shown in adjacent columns, aligned by line number
+type sideBySide struct {
+	leftLabel, rightLabel string
+	left, right           []string
+	leftWidth             int // Width of the left column, 0 to split the width evenly
+}
+
+type sideBySideLoadedMsg struct {
+	view sideBySide
+	err  error
+}
+
+// renderSideBySide lays out both texts in columns that fit within width
+func renderSideBySide(sbs sideBySide, width int) string {
+	leftWidth := (width - 3) / 2 // " │ " separator
+	if sbs.leftWidth > 0 {
+		leftWidth = min(sbs.leftWidth, leftWidth)
+	}
+	leftWidth = max(leftWidth, 1)
+	rightWidth := max(width-3-leftWidth, 1)
+	column := func(s string, colWidth int) string {
+		s = ansi.Truncate(strings.ReplaceAll(s, "\t", "    "), colWidth, "…")
+		return s + strings.Repeat(" ", max(colWidth-ansi.StringWidth(s), 0))
+	}
+
+	labelStyle := lipgloss.NewStyle().Bold(true)
+	rows := []string{
+		labelStyle.Render(column(sbs.leftLabel, leftWidth)) + " │ " + labelStyle.Render(column(sbs.rightLabel, rightWidth)),
+		strings.Repeat("─", leftWidth) + "─┼─" + strings.Repeat("─", rightWidth),
+	}
+	for i := 0; i < max(len(sbs.left), len(sbs.right)); i++ {
+		var l, r string
+		if i < len(sbs.left) {
+			l = sbs.left[i]
+		}
+		if i < len(sbs.right) {
+			r = sbs.right[i]
+		}
+		rows = append(rows, column(l, leftWidth)+" │ "+column(r, rightWidth))
+	}
+	return strings.Join(rows, "\n")
+}
+
+// markCommit remembers the commit shown in single-file mode for a side-by-side comparison
+func (m *Model) markCommit() tea.Cmd {
+	hash, ok := m.currentCommitForSource()
+	if !ok {
+		return nil
+	}
+	m.markedCommit = hash
+	return m.setStatus("Marked " + hash + " — press M on another commit to compare")
+}
+
+// compareMarked loads the marked and current versions of the file for side-by-side viewing
+func (m *Model) compareMarked() tea.Cmd {
+	if m.markedCommit == "" {
+		return m.setStatus("Mark a commit with m first")
+	}
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	file, marked := m.currentFile, m.markedCommit
+	return func() tea.Msg {
+		left, err := m.gitService.GetFileContentAtCommit(file, marked)
+		if err != nil {
+			return sideBySideLoadedMsg{err: err}
+		}
+		right, err := m.gitService.GetFileContentAtCommit(file, hash)
+		if err != nil {
+			return sideBySideLoadedMsg{err: err}
+		}
+		return sideBySideLoadedMsg{view: sideBySide{
+			leftLabel:  fmt.Sprintf("%s (marked)", marked),
+			rightLabel: hash,
+			left:       strings.Split(strings.TrimRight(left, "\n"), "\n"),
+			right:      strings.Split(strings.TrimRight(right, "\n"), "\n"),
+		}}
+	}
+}
+
+// blameLineRegex splits a blame line into its annotation, which ends with the line
+// number and ")", and the annotated code
+var blameLineRegex = regexp.MustCompile(`^(.*?\s\d+\)) ?(.*)$`)
+
+// toggleBlameSplit switches single-file mode between the display mode and blame beside the file
+func (m *Model) toggleBlameSplit() tea.Cmd {
+	m.blameSplit = !m.blameSplit
+	return m.loadContentForCurrentSource()
+}
+
+// loadBlameSplit lays out the file's blame annotations beside the lines they annotate
+func (m *Model) loadBlameSplit(file, hash string) tea.Msg {
+	rev := hash
+	if status, _ := m.gitService.GetFileStatusAtCommit(file, hash); status == "D" {
+		// The file no longer exists at this commit, so blame its last version
+		rev = hash + "^"
+	}
+	blame, err := m.gitService.GetBlame(file, rev)
+	if err != nil {
+		return sideBySideLoadedMsg{err: err}
+	}
+	return sideBySideLoadedMsg{view: blameColumns(blame, file+" @ "+rev)}
+}
+
+// blameColumns splits blame output into annotations on the left and code on the right
+func blameColumns(blame, label string) sideBySide {
+	view := sideBySide{leftLabel: "blame", rightLabel: label}
+	for _, line := range strings.Split(strings.TrimRight(blame, "\n"), "\n") {
+		annotation, code := line, ""
+		if parts := blameLineRegex.FindStringSubmatch(line); parts != nil {
+			annotation, code = parts[1], parts[2]
+		}
+		view.left = append(view.left, annotation)
+		view.right = append(view.right, code)
+		view.leftWidth = max(view.leftWidth, ansi.StringWidth(annotation))
+	}
+	return view
+}
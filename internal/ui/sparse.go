@@ -0,0 +1,18 @@
+package ui
+
+// checkedOutPaths drops the files a sparse checkout leaves out of the working tree, so
+// the tree only lists files that are on disk. The full list is kept if the index can't
+// be read.
+func (m *Model) checkedOutPaths(paths []string) []string {
+	skipped, err := m.gitService.GetSkippedPaths()
+	if err != nil || len(skipped) == 0 {
+		return paths
+	}
+	kept := make([]string, 0, len(paths))
+	for _, path := range paths {
+		if !skipped[path] {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
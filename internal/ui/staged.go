@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// stagedEntry stands in for a commit while the commit list shows the index
+var stagedEntry = git.Commit{Hash: "index", Message: "Staged changes"}
+
+type hunkUnstagedMsg struct {
+	offset int
+	err    error
+}
+
+// toggleStagedView switches the commit list between recent commits and the staged changes
+func (m *Model) toggleStagedView() tea.Cmd {
+	m.preview = nil
+	m.commitIndex = 0
+	if m.repoView == viewStaged {
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadInitialData
+	}
+	m.repoView = viewStaged
+	m.commits = []git.Commit{stagedEntry}
+	m.populateCommitList(m.commits)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(0)
+	return m.loadFilesForCurrentCommit
+}
+
+// unstageCurrentHunk unstages the hunk at the top of the diff view
+func (m *Model) unstageCurrentHunk() tea.Cmd {
+	hunk := m.diffView.CurrentHunk()
+	if len(hunk) == 0 || m.currentFile == "" {
+		return m.setStatus("No hunk to unstage")
+	}
+	file := m.currentFile
+	offset := m.diffView.YOffset()
+	return func() tea.Msg {
+		err := m.gitService.UnstageHunk(file, strings.Join(hunk, "\n"))
+		return hunkUnstagedMsg{offset: offset, err: err}
+	}
+}
+
+// applyHunkUnstaged reloads the staged files, staying on the same file and scroll position
+func (m *Model) applyHunkUnstaged(msg hunkUnstagedMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Unstage failed: %v", msg.err))
+	}
+	m.dirtiedIndex = true
+	m.restoreFile = m.currentFile
+	m.pendingOffset = msg.offset
+	return tea.Batch(m.setStatus("Unstaged hunk"), m.loadFilesForCurrentCommit)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const discardPrompt = "Discard working tree changes to %s? (y/n)"
+
+type fileActionMsg struct {
+	action string // What was done, e.g. "Stage"
+	done   string // Its past tense for the status, e.g. "Staged"
+	file   string
+	err    error
+}
+
+// runFileAction applies a file-level action to the selected file
+func (m *Model) runFileAction(action, done string, run func(string) error) tea.Cmd {
+	file := m.currentFile
+	if file == "" {
+		return m.setStatus("No file selected")
+	}
+	return func() tea.Msg {
+		return fileActionMsg{action: action, done: done, file: file, err: run(file)}
+	}
+}
+
+// stageCurrentFile adds the selected file to the index
+func (m *Model) stageCurrentFile() tea.Cmd {
+	return m.runFileAction("Stage", "Staged", m.gitService.StageFile)
+}
+
+// unstageCurrentFile resets the selected file in the index to HEAD
+func (m *Model) unstageCurrentFile() tea.Cmd {
+	return m.runFileAction("Unstage", "Unstaged", m.gitService.UnstageFile)
+}
+
+// promptDiscard asks before throwing away the selected file's working tree changes
+func (m *Model) promptDiscard() tea.Cmd {
+	if m.currentFile == "" {
+		return m.setStatus("No file selected")
+	}
+	m.confirmingDiscard = m.currentFile
+	return nil
+}
+
+// answerDiscardPrompt discards on y and cancels on anything else
+func (m *Model) answerDiscardPrompt(msg tea.KeyMsg) tea.Cmd {
+	file := m.confirmingDiscard
+	m.confirmingDiscard = ""
+	if msg.String() != "y" && msg.String() != "Y" {
+		return m.setStatus("Discard cancelled")
+	}
+	return func() tea.Msg {
+		return fileActionMsg{action: "Discard", done: "Discarded changes to", file: file, err: m.gitService.DiscardFile(file)}
+	}
+}
+
+// applyFileAction reloads the file list and diff, staying on the same file if it's still listed
+func (m *Model) applyFileAction(msg fileActionMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("%s failed: %v", msg.action, msg.err))
+	}
+	if msg.action != "Discard" {
+		m.dirtiedIndex = true
+	}
+	m.restoreFile = m.currentFile
+	m.pendingOffset = m.diffView.YOffset()
+	return tea.Batch(m.setStatus(msg.done+" "+msg.file), m.loadFilesForCurrentCommit)
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type stashesLoadedMsg struct {
+	stashes []git.Commit
+	err     error
+}
+
+// cycleStashView steps through commits -> stashes vs parent -> stashes vs working tree
+func (m *Model) cycleStashView() tea.Cmd {
+	m.preview = nil
+	m.commitIndex = 0
+	switch {
+	case m.repoView != viewStashes:
+		m.repoView = viewStashes
+		m.stashBase = git.StashBaseParent
+		return m.loadStashes
+	case m.stashBase == git.StashBaseParent:
+		m.stashBase = git.StashBaseWorkingTree
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadFilesForCurrentCommit
+	default:
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadInitialData
+	}
+}
+
+// commitListTitle returns the commit list title for the current repo view and glob
+func (m *Model) commitListTitle() string {
+	if glob := m.gitService.Pathspec(); glob != "" {
+		return m.repoViewTitle() + " [" + glob + "]"
+	}
+	return m.repoViewTitle()
+}
+
+// repoViewTitle names what the commit list shows
+func (m *Model) repoViewTitle() string {
+	switch m.repoView {
+	case viewStaged:
+		return "Staged"
+	case viewWorkingCopy:
+		return "Working copy"
+	case viewReflog:
+		return "Reflog (HEAD)"
+	case viewPR:
+		if m.prDivergence != "" {
+			return "PR: " + m.prView.Branch + " " + m.prDivergence
+		}
+		return "PR: " + m.prView.Branch
+	case viewRefs:
+		return "Refs"
+	case viewCommits:
+		title := "Commits"
+		if m.allRefs {
+			title = "Commits (all branches)"
+		} else if m.logStart != "" {
+			title = "Commits from " + m.logStart
+		}
+		if m.typeFilter != "" {
+			return title + " (" + m.typeFilter + ")"
+		}
+		return title
+	}
+	if m.stashBase == git.StashBaseWorkingTree {
+		return "Stashes (vs working tree)"
+	}
+	return "Stashes (vs parent)"
+}
+
+func (m *Model) loadStashes() tea.Msg {
+	stashes, err := m.gitService.GetStashes()
+	return stashesLoadedMsg{stashes: stashes, err: err}
+}
+
+func (m *Model) applyStashes(msg stashesLoadedMsg) tea.Cmd {
+	if msg.err != nil || len(msg.stashes) == 0 {
+		// Fall back to the commit list, which may have been replaced by another view
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		status := "No stashes"
+		if msg.err != nil {
+			status = "Failed to list stashes: " + msg.err.Error()
+		}
+		return tea.Batch(m.setStatus(status), m.loadInitialData)
+	}
+	m.commits = msg.stashes
+	m.populateCommitList(msg.stashes)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(m.commitIndex)
+	return m.loadFilesForCurrentCommit
+}
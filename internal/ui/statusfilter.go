@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// statusFilters are the file statuses % cycles through, after showing every file
+var statusFilters = []struct {
+	status string
+	name   string
+}{
+	{"A", "added"},
+	{"D", "deleted"},
+	{"M", "modified"},
+	{"R", "renamed"},
+}
+
+// cycleStatusFilter limits the file list to the next status in turn, then back to every file
+func (m *Model) cycleStatusFilter() tea.Cmd {
+	next := 0
+	for i, f := range statusFilters {
+		if f.status == m.statusFilter {
+			next = i + 1
+		}
+	}
+	if next < len(statusFilters) {
+		m.statusFilter = statusFilters[next].status
+		m.sidebar.SetStatusFilter(statusFilters[next].name)
+	} else {
+		m.statusFilter = ""
+		m.sidebar.SetStatusFilter("")
+	}
+	m.restoreFile = m.currentFile
+	return m.loadFilesForCurrentCommit
+}
+
+// statusFilterName names the active status filter, empty when there is none
+func (m *Model) statusFilterName() string {
+	for _, f := range statusFilters {
+		if f.status == m.statusFilter {
+			return f.name
+		}
+	}
+	return ""
+}
+
+// filterByStatus drops the files the status filter excludes. Untracked files count as added.
+func (m *Model) filterByStatus(files []FileItem) []FileItem {
+	if m.statusFilter == "" {
+		return files
+	}
+	var kept []FileItem
+	for _, f := range files {
+		if strings.HasPrefix(f.Status, m.statusFilter) || (m.statusFilter == "A" && f.Status == "??") {
+			kept = append(kept, f)
+		}
+	}
+	return kept
+}
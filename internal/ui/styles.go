@@ -23,6 +23,46 @@ var (
 			Foreground(lipgloss.Color("4")). // blue like lazygit optionsTextColor
 			Padding(0, 1)
 
+	// Transient status message in the help bar
+	StatusStyle = lipgloss.NewStyle().
+			Foreground(ColorWarning)
+
+	// Notice pinned above the diff content
+	BannerStyle = lipgloss.NewStyle().
+			Foreground(ColorError).
+			Bold(true).
+			Padding(0, 1)
+
+	// Trailers section of the commit description
+	TrailerHeaderStyle = lipgloss.NewStyle().
+				Foreground(ColorInfo).
+				Bold(true)
+
+	TrailerKeyStyle = lipgloss.NewStyle().
+			Foreground(ColorPrimary)
+
+	// Card the commit description's header is drawn in
+	CardStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(ColorSecondary).
+			Padding(0, 1)
+
+	CardHashStyle = lipgloss.NewStyle().
+			Foreground(ColorWarning).
+			Bold(true)
+
+	CardRefsStyle = lipgloss.NewStyle().
+			Foreground(ColorInfo)
+
+	CardAuthorStyle = lipgloss.NewStyle().
+			Foreground(ColorSuccess)
+
+	CardDateStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("4"))
+
+	CardSubjectStyle = lipgloss.NewStyle().
+				Bold(true)
+
 	// Mode badges for help bar (using hex colors for consistent contrast)
 	ModeBadgeCommits = lipgloss.NewStyle().
 				Background(lipgloss.Color("#2d7d9a")).
@@ -59,4 +99,14 @@ var (
 			Foreground(lipgloss.Color("#ffffff")).
 			Bold(true).
 			Padding(0, 1)
+
+	// Marker beside the lines of the author whose lines are picked out
+	AuthorMarkStyle = lipgloss.NewStyle().
+			Foreground(ColorInfo).
+			Bold(true)
+
+	// Stash badge beside the stashes interleaved into a file's history
+	StashBadgeStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("5")).
+			Bold(true)
 )
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// submoduleLineRegex matches the gitlink lines of a submodule diff ("+Subproject commit <sha>")
+var submoduleLineRegex = regexp.MustCompile(`^([-+])Subproject commit ([0-9a-f]+)`)
+
+// parseSubmoduleBump extracts the old and new commits from a submodule pointer diff,
+// reporting false unless both are present (not an added or removed submodule)
+func parseSubmoduleBump(content string) (oldSha, newSha string, ok bool) {
+	for _, line := range strings.Split(content, "\n") {
+		m := submoduleLineRegex.FindStringSubmatch(stripANSI(line))
+		if m == nil {
+			continue
+		}
+		if m[1] == "-" {
+			oldSha = m[2]
+		} else {
+			newSha = m[2]
+		}
+	}
+	return oldSha, newSha, oldSha != "" && newSha != ""
+}
+
+// shortSha abbreviates a commit hash for display
+func shortSha(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// summarizeSubmodule replaces a submodule pointer diff with a summary of the commits
+// it moved across, or returns content unchanged if it isn't one
+func (m *Model) summarizeSubmodule(file, content string) string {
+	oldSha, newSha, ok := parseSubmoduleBump(content)
+	if !ok {
+		return content
+	}
+
+	header := fmt.Sprintf("submodule %s: %s → %s", file, shortSha(oldSha), shortSha(newSha))
+	commits, err := m.gitService.GetSubmoduleLog(file, oldSha, newSha)
+	if err != nil {
+		return header + "\n\n(submodule not checked out; commit list unavailable)"
+	}
+	rewound := false
+	if len(commits) == 0 {
+		// The pointer may have moved backwards
+		commits, _ = m.gitService.GetSubmoduleLog(file, newSha, oldSha)
+		rewound = len(commits) > 0
+	}
+
+	count := fmt.Sprintf("%d commits", len(commits))
+	if len(commits) == 1 {
+		count = "1 commit"
+	}
+	prefix := "  "
+	if rewound {
+		count = "rewound " + count
+		prefix = "- "
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n\n", header, count)
+	for _, c := range commits {
+		fmt.Fprintf(&b, "%s\x1b[33m%s\x1b[0m %s\n", prefix, c.Hash, c.Message)
+	}
+	return b.String()
+}
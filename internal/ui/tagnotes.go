@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"strings"
+
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tagNotes are the annotated tags pointing at a commit, shown in its description card
+type tagNotes struct {
+	hash string
+	tags []git.AnnotatedTag
+}
+
+type tagNotesMsg struct {
+	notes tagNotes
+}
+
+// decoratedTags reads the commit hash and the names of the tags pointing at it from the
+// decorated commit line of git show output, e.g. "commit 1a2b (tag: v1.0, main)"
+func decoratedTags(content string) (string, []string) {
+	first, _, _ := strings.Cut(content, "\n")
+	rest, ok := strings.CutPrefix(stripANSI(first), "commit ")
+	if !ok {
+		return "", nil
+	}
+	hash, refs, _ := strings.Cut(rest, " ")
+	refs = strings.TrimSuffix(strings.TrimPrefix(refs, "("), ")")
+	var names []string
+	for _, ref := range strings.Split(refs, ", ") {
+		if name, ok := strings.CutPrefix(ref, "tag: "); ok {
+			names = append(names, name)
+		}
+	}
+	return hash, names
+}
+
+// loadTagNotes fetches the annotated tags of the commit whose description is shown, once
+// per commit. Tags are only looked up for commits decorated with one.
+func (m *Model) loadTagNotes() tea.Cmd {
+	if !m.diffView.showDescription {
+		return nil
+	}
+	hash, names := decoratedTags(m.diffView.RawContent())
+	if len(names) == 0 || hash == m.diffView.tagNotes.hash {
+		return nil
+	}
+	return func() tea.Msg {
+		tags, _ := m.gitService.GetAnnotatedTags(names)
+		return tagNotesMsg{notes: tagNotes{hash: hash, tags: tags}}
+	}
+}
+
+// SetTagNotes sets the annotated tags shown in the description card of their commit
+func (d *DiffView) SetTagNotes(notes tagNotes) {
+	d.tagNotes = notes
+	if d.showDescription {
+		d.updateContent()
+	}
+}
+
+// tagSection lists annotated tags with who tagged them, when, and their messages
+func tagSection(tags []git.AnnotatedTag) []string {
+	var section []string
+	for _, tag := range tags {
+		heading := TrailerHeaderStyle.Render("Tag "+tag.Name) + "  " + CardAuthorStyle.Render(tag.Tagger) + "  " + CardDateStyle.Render(tag.Date)
+		if tag.Signed {
+			heading += "  " + SubtitleStyle.Render("signed")
+		}
+		section = append(section, heading)
+		for _, line := range strings.Split(strings.TrimRight(tag.Message, "\n"), "\n") {
+			section = append(section, "    "+line)
+		}
+	}
+	return section
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+type tagCompareMsg struct {
+	tag, hash string
+	content   string
+	full      bool // content is the file at the tag rather than a diff
+	err       error
+}
+
+type nearestTagMsg struct {
+	hash, tag string
+}
+
+// promptTagCompare asks for a tag to compare the current version of the file against,
+// starting from the newest tag before it
+func (m *Model) promptTagCompare() tea.Cmd {
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	m.textInput.SetValue("")
+	m.textInput.Placeholder = "v1.2.0"
+	m.textInput.Focus()
+	m.textInputMode = "tag"
+	return tea.Batch(textinput.Blink, func() tea.Msg {
+		tag, _ := m.gitService.GetNearestTag(hash)
+		return nearestTagMsg{hash: hash, tag: tag}
+	})
+}
+
+// applyNearestTag fills in the tag prompt, unless it was closed or typed into while
+// the tag was being looked up
+func (m *Model) applyNearestTag(msg nearestTagMsg) {
+	if m.textInputMode != "tag" || m.textInput.Value() != "" || msg.tag == "" {
+		return
+	}
+	if hash, ok := m.currentCommitForSource(); !ok || hash != msg.hash {
+		return
+	}
+	m.textInput.SetValue(msg.tag)
+	m.textInput.CursorEnd()
+}
+
+// loadTagCompare diffs the file from the tag to the selected commit, or shows the
+// file as it was at the tag in full-file mode
+func (m *Model) loadTagCompare(tag string) tea.Cmd {
+	hash, ok := m.currentCommitForSource()
+	if !ok || m.currentFile == "" {
+		return nil
+	}
+	file := m.currentFile
+	full := m.displayMode == displayFull
+	return func() tea.Msg {
+		tagHash, err := m.gitService.ResolveTag(tag)
+		if err != nil {
+			return tagCompareMsg{tag: tag, err: err}
+		}
+		var content string
+		if full {
+			content, err = m.gitService.GetFileContentAtCommit(file, tagHash)
+		} else {
+			content, err = m.gitService.GetDiffBetweenCommits(file, tagHash, hash)
+		}
+		return tagCompareMsg{tag: tag, hash: hash, content: content, full: full, err: err}
+	}
+}
+
+// applyTagCompare shows the comparison in the diff view until the next navigation
+func (m *Model) applyTagCompare(msg tagCompareMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.setStatus(fmt.Sprintf("Tag compare failed: %v", msg.err))
+	}
+	if msg.full {
+		m.diffView.SetBanner(fmt.Sprintf("%s at %s", m.currentFile, msg.tag))
+		m.diffView.SetContent(msg.content)
+		return nil
+	}
+	if !hasHunk(msg.content) {
+		return m.setStatus(fmt.Sprintf("%s unchanged since %s", m.currentFile, msg.tag))
+	}
+	m.diffView.SetBanner(fmt.Sprintf("Comparing %s → %s", msg.tag, msg.hash))
+	m.diffView.SetContent(msg.content)
+	return nil
+}
@@ -0,0 +1,23 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// toggleTextconv turns the repository's textconv filters on or off and reloads the content
+func (m *Model) toggleTextconv() tea.Cmd {
+	on := !m.gitService.Textconv()
+	m.gitService.SetTextconv(on)
+	msg := "textconv filters off"
+	if on {
+		msg = "textconv filters on"
+	}
+	status := m.setStatus(msg)
+	if m.showFileTree || m.currentFile == "" {
+		return status
+	}
+	if m.singleFileMode {
+		return tea.Batch(status, m.loadContentForCurrentSource())
+	}
+	return tea.Batch(status, m.loadDiffForCurrentFile)
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"path/filepath"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// windowTitleText describes the session for the terminal title, e.g.
+// "var: myrepo — internal/git/service.go @ abc1234"
+func (m *Model) windowTitleText() string {
+	title := "var: " + filepath.Base(m.gitService.RepoPath())
+
+	var hash string
+	if m.singleFileMode {
+		hash, _ = m.currentCommitForSource()
+	} else if m.commitIndex < len(m.commits) {
+		hash = m.commits[m.commitIndex].Hash
+	}
+	if len(hash) > 7 {
+		hash = hash[:7]
+	}
+
+	switch {
+	case m.currentFile != "" && hash != "":
+		title += " — " + m.currentFile + " @ " + hash
+	case m.currentFile != "":
+		title += " — " + m.currentFile
+	case hash != "":
+		title += " @ " + hash
+	}
+	return title
+}
+
+// updateWindowTitle sets the terminal title when the session's context changed
+func (m *Model) updateWindowTitle() tea.Cmd {
+	if !m.config.WindowTitle {
+		return nil
+	}
+	title := m.windowTitleText()
+	if title == m.windowTitle {
+		return nil
+	}
+	m.windowTitle = title
+	return tea.SetWindowTitle(title)
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// treeCacheSize bounds how many file previews are kept; the cache is emptied when full
+	treeCacheSize = 500
+	// treePrefetchWorkers bounds how many previews are fetched at once when a directory opens
+	treePrefetchWorkers = 4
+)
+
+// treeCache holds tree previews by commit and path. It is shared by pointer, as the
+// Model is copied on every update and prefetches fill it in the background.
+type treeCache struct {
+	mu      sync.Mutex
+	entries map[string]string
+}
+
+func newTreeCache() *treeCache {
+	return &treeCache{entries: make(map[string]string)}
+}
+
+func treeCacheKey(commit, path string) string {
+	return commit + ":" + path
+}
+
+func (c *treeCache) get(commit, path string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	content, ok := c.entries[treeCacheKey(commit, path)]
+	return content, ok
+}
+
+func (c *treeCache) put(commit, path, content string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= treeCacheSize {
+		c.entries = make(map[string]string)
+	}
+	c.entries[treeCacheKey(commit, path)] = content
+}
+
+// prefetchTreeDir fetches the previews of a directory's files in the background, so
+// moving onto them shows them at once
+func (m *Model) prefetchTreeDir(dir string) tea.Cmd {
+	var paths []string
+	commit := m.treeCommit()
+	for _, p := range m.fileTree.FileChildren(dir) {
+		if _, ok := m.treeCache.get(commit, p); !ok {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, treePrefetchWorkers)
+		for _, p := range paths {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(p string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if content, err := m.fetchTreePreview(commit, p); err == nil {
+					m.treeCache.put(commit, p, content)
+				}
+			}(p)
+		}
+		wg.Wait()
+		return nil
+	}
+}
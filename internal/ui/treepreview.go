@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// treePreviewDelay debounces previews while moving quickly through the tree
+	treePreviewDelay = 150 * time.Millisecond
+	// treePreviewLines caps how much of a file the hover preview shows
+	treePreviewLines = 200
+)
+
+type treePreviewMsg struct {
+	id   int
+	path string
+}
+
+// scheduleTreePreview previews the highlighted tree entry once the selection settles
+func (m *Model) scheduleTreePreview() tea.Cmd {
+	path := m.fileTree.SelectedPath()
+	if path == "" {
+		return nil
+	}
+	m.treePreviewID++
+	id := m.treePreviewID
+	return tea.Tick(treePreviewDelay, func(time.Time) tea.Msg {
+		return treePreviewMsg{id: id, path: path}
+	})
+}
+
+// loadTreePreview shows the head of a file, or a listing of a directory's children
+func (m *Model) loadTreePreview(path string) tea.Cmd {
+	label := m.treeLabel()
+	m.diffView.SetFileInfo(path, 0, 1, label)
+	if m.fileTree.IsSelectedDir() {
+		return func() tea.Msg {
+			return diffLoadedMsg{content: m.fileTree.DirSummary(path), banner: "Preview (" + label + ")"}
+		}
+	}
+	commit := m.treeCommit()
+	return func() tea.Msg {
+		content, ok := m.treeCache.get(commit, path)
+		if !ok {
+			var err error
+			content, err = m.fetchTreePreview(commit, path)
+			if err != nil {
+				return diffLoadedMsg{content: fmt.Sprintf("Error: %v", err)}
+			}
+			m.treeCache.put(commit, path, content)
+		}
+		return diffLoadedMsg{content: content, banner: "Preview (" + label + ") — enter to open history"}
+	}
+}
+
+// fetchTreePreview reads the head of a file at commit
+func (m *Model) fetchTreePreview(commit, path string) (string, error) {
+	content, err := m.gitService.GetFileContentAtCommit(path, commit)
+	if err != nil {
+		return "", err
+	}
+	lines := strings.SplitAfter(content, "\n")
+	if len(lines) > treePreviewLines {
+		content = strings.Join(lines[:treePreviewLines], "") + "…\n"
+	}
+	return content, nil
+}
+
+// treeCommit is the commit the tree shows, resolved so cached previews go stale when HEAD moves
+func (m *Model) treeCommit() string {
+	if m.treeHead != "" {
+		return m.treeHead
+	}
+	return "HEAD"
+}
@@ -0,0 +1,31 @@
+package ui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// treeRevision is the commit the tree should list: the selected commit when the tree is
+// switched to it with ^, otherwise empty for HEAD
+func (m *Model) treeRevision() string {
+	if !m.treeAtCommit || m.commitIndex >= len(m.commits) {
+		return ""
+	}
+	return m.commits[m.commitIndex].Hash
+}
+
+// toggleTreeRevision switches the tree between HEAD and the selected commit, to browse
+// the repository as it was at that revision
+func (m *Model) toggleTreeRevision() tea.Cmd {
+	m.treeAtCommit = !m.treeAtCommit
+	if m.treeAtCommit && m.treeRevision() == "" {
+		m.treeAtCommit = false
+		return m.setStatus("No commit selected")
+	}
+	return m.loadTreeFiles
+}
+
+// treeLabel names the revision the tree lists, for its previews
+func (m *Model) treeLabel() string {
+	if rev := m.treeRevision(); rev != "" {
+		return rev
+	}
+	return "HEAD"
+}
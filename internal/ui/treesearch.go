@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// treeMatchStyle marks the part of a name the tree search matched
+var treeMatchStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true).Underline(true)
+
+// StartSearch begins searching the tree; keys go to the query until enter or esc
+func (ft *FileTree) StartSearch() {
+	ft.searching = true
+	ft.searchQuery = ""
+	ft.searchTitle = ft.list.Title
+	ft.searchOrigin = ft.SelectedPath()
+	ft.searchExpanded = make(map[string]bool, len(ft.expanded))
+	for dir, open := range ft.expanded {
+		ft.searchExpanded[dir] = open
+	}
+	ft.updateSearchTitle()
+}
+
+// IsSearching reports whether the tree search is capturing keys
+func (ft *FileTree) IsSearching() bool {
+	return ft.searching
+}
+
+// stopSearch shows the whole tree again with the directories expanded as before the
+// search, plus those leading to the selection
+func (ft *FileTree) stopSearch(selected string) {
+	ft.searching = false
+	ft.searchHits = nil
+	ft.list.Title = ft.searchTitle
+	ft.list.SetDelegate(treeItemDelegate{})
+	ft.expanded = ft.searchExpanded
+	for dir := parentDir(selected); dir != ""; dir = parentDir(dir) {
+		ft.expanded[dir] = true
+	}
+	ft.rebuildVisibleItems()
+	ft.selectPath(selected)
+}
+
+func (ft *FileTree) updateSearchTitle() {
+	title := ft.searchTitle + " /" + ft.searchQuery
+	if ft.searchQuery != "" && ft.searchHits == nil {
+		title += " (no match)"
+	}
+	ft.list.Title = title
+}
+
+// applySearch narrows the tree to the files whose path contains the query and the
+// directories holding them, all expanded, and selects the first match
+func (ft *FileTree) applySearch() {
+	ft.searchHits = nil
+	query := strings.ToLower(ft.searchQuery)
+	first := ""
+	if query != "" {
+		for _, node := range ft.allNodes {
+			if node.IsDir || !strings.Contains(strings.ToLower(node.Path), query) {
+				continue
+			}
+			if ft.searchHits == nil {
+				ft.searchHits = make(map[string]bool)
+				first = node.Path
+			}
+			ft.searchHits[node.Path] = true
+			for dir := parentDir(node.Path); dir != ""; dir = parentDir(dir) {
+				ft.searchHits[dir] = true
+			}
+		}
+	}
+	ft.list.SetDelegate(treeItemDelegate{query: ft.searchQuery})
+	ft.rebuildVisibleItems()
+	if first != "" {
+		ft.selectPath(first)
+	} else if query == "" {
+		ft.selectPath(ft.searchOrigin)
+	}
+	ft.updateSearchTitle()
+}
+
+// nextMatch selects the next matching file in the given direction, wrapping around
+func (ft *FileTree) nextMatch(step int) {
+	items := ft.list.Items()
+	for n := 1; n <= len(items); n++ {
+		idx := ((ft.list.Index()+n*step)%len(items) + len(items)) % len(items)
+		if item, ok := items[idx].(TreeItem); ok && !item.Node.IsDir {
+			ft.list.Select(idx)
+			return
+		}
+	}
+}
+
+// updateSearch edits the query: enter keeps the selected file, esc goes back to where
+// the search started, ctrl+n/ctrl+p move to the next/previous match
+func (ft *FileTree) updateSearch(msg tea.KeyMsg) {
+	switch msg.Type {
+	case tea.KeyEnter:
+		ft.stopSearch(ft.SelectedPath())
+	case tea.KeyEsc:
+		ft.stopSearch(ft.searchOrigin)
+	case tea.KeyCtrlN, tea.KeyDown:
+		ft.nextMatch(1)
+	case tea.KeyCtrlP, tea.KeyUp:
+		ft.nextMatch(-1)
+	case tea.KeyBackspace:
+		if query := []rune(ft.searchQuery); len(query) > 0 {
+			ft.searchQuery = string(query[:len(query)-1])
+		}
+		ft.applySearch()
+	case tea.KeyRunes, tea.KeySpace:
+		ft.searchQuery += string(msg.Runes)
+		ft.applySearch()
+	}
+}
+
+// selectPath selects the row of a path when it is shown
+func (ft *FileTree) selectPath(p string) {
+	for idx, li := range ft.list.Items() {
+		if t, ok := li.(TreeItem); ok && t.Node.Path == p {
+			ft.list.Select(idx)
+			return
+		}
+	}
+}
+
+// parentDir returns the directory holding a path, empty at the top level
+func parentDir(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i < 0 {
+		return ""
+	}
+	return p[:i]
+}
+
+// highlightMatch marks where query occurs in name, ignoring case
+func highlightMatch(name, query string) string {
+	i := strings.Index(strings.ToLower(name), strings.ToLower(query))
+	if query == "" || i < 0 || len(strings.ToLower(name)) != len(name) || len(strings.ToLower(query)) != len(query) {
+		return name
+	}
+	end := i + len(query)
+	return name[:i] + treeMatchStyle.Render(name[i:end]) + name[end:]
+}
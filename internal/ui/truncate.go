@@ -0,0 +1,74 @@
+package ui
+
+import "strings"
+
+// TruncateMode selects how paths too long for the file list are shortened
+type TruncateMode int
+
+const (
+	TruncateKeepBasename TruncateMode = iota // src/…/service.go (default)
+	TruncateLeading                          // …/internal/git/service.go
+	TruncateBasenameOnly                     // service.go
+	TruncateStart                            // src…git/service.go
+)
+
+// parseTruncateMode maps a configured strategy name to a TruncateMode, defaulting to keep-basename
+func parseTruncateMode(name string) TruncateMode {
+	switch name {
+	case "leading":
+		return TruncateLeading
+	case "basename-only":
+		return TruncateBasenameOnly
+	case "start":
+		return TruncateStart
+	default:
+		return TruncateKeepBasename
+	}
+}
+
+// truncatePath shortens a path to fit within maxLen runes using the given strategy
+func truncatePath(path string, maxLen int, mode TruncateMode) string {
+	runes := []rune(path)
+	if len(runes) <= maxLen || maxLen <= 5 {
+		return path
+	}
+
+	slash := strings.LastIndex(path, "/")
+	base := []rune(path[slash+1:])
+	dir := []rune(path[:slash+1])
+
+	switch mode {
+	case TruncateLeading:
+		tail := string(runes[len(runes)-(maxLen-1):])
+		// Start at a directory boundary when one is in view
+		if i := strings.Index(tail, "/"); i >= 0 && i < len(tail)-1 {
+			tail = tail[i:]
+		}
+		return "…" + tail
+	case TruncateBasenameOnly:
+		return tailFit(base, maxLen)
+	case TruncateStart:
+		// Show first 3 chars + … + end
+		endLen := maxLen - 4 // 3 for start + 1 for …
+		return string(runes[:3]) + "…" + string(runes[len(runes)-endLen:])
+	default:
+		head := maxLen - len(base) - 2 // room left after "…/" + basename
+		if head < 1 || slash < 0 {
+			return tailFit(base, maxLen)
+		}
+		prefix := string(dir[:min(head, len(dir))])
+		// Keep whole leading directories when at least one fits
+		if i := strings.LastIndex(prefix, "/"); i >= 0 {
+			prefix = prefix[:i+1]
+		}
+		return prefix + "…/" + string(base)
+	}
+}
+
+// tailFit returns s, or its end behind an ellipsis if it is longer than maxLen runes
+func tailFit(s []rune, maxLen int) string {
+	if len(s) <= maxLen {
+		return string(s)
+	}
+	return "…" + string(s[len(s)-(maxLen-1):])
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"var/internal/git"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// workingCopyEntry stands in for a commit while the commit list shows the working copy
+var workingCopyEntry = git.Commit{Hash: "worktree", Message: "Working copy vs HEAD"}
+
+// workingCopyStartMsg opens var on the working copy because it has uncommitted changes
+type workingCopyStartMsg struct {
+	headLabel string
+}
+
+// loadStartupData loads the commit list, or the working copy view when the working tree
+// is dirty unless the config asks to always start on the commits
+func (m *Model) loadStartupData() tea.Msg {
+	if m.config.StartupView != "commits" && (m.config.StartupView == "changes" || m.gitService.IsDirty()) {
+		return workingCopyStartMsg{headLabel: m.currentHeadLabel()}
+	}
+	return m.loadInitialData()
+}
+
+// toggleWorkingCopyView switches the commit list between recent commits and everything
+// changed since HEAD
+func (m *Model) toggleWorkingCopyView() tea.Cmd {
+	if m.repoView == viewWorkingCopy {
+		m.preview = nil
+		m.commitIndex = 0
+		m.repoView = viewCommits
+		m.commitList.SetTitle(m.commitListTitle())
+		return m.loadInitialData
+	}
+	return m.showWorkingCopy()
+}
+
+// showWorkingCopy lists every modified, staged and untracked file, each diffed against HEAD
+func (m *Model) showWorkingCopy() tea.Cmd {
+	m.preview = nil
+	m.commitIndex = 0
+	m.repoView = viewWorkingCopy
+	m.commits = []git.Commit{workingCopyEntry}
+	m.populateCommitList(m.commits)
+	m.commitList.SetTitle(m.commitListTitle())
+	m.commitList.SelectIndex(0)
+	return m.loadFilesForCurrentCommit
+}
+
+// workingCopyFiles lists the files changed since HEAD with their line counts
+func (m *Model) workingCopyFiles() []FileItem {
+	changed, _ := m.gitService.GetWorkingCopyFiles()
+	stats, _ := m.gitService.GetWorkingCopyNumstat()
+	var files []FileItem
+	for _, f := range changed {
+		item := FileItem{Path: f.Path, Status: f.Status, OldPath: f.OldPath, Similarity: f.Similarity}
+		if s, ok := stats[f.Path]; ok {
+			item.Additions = s.Additions
+			item.Deletions = s.Deletions
+		}
+		files = append(files, item)
+	}
+	return files
+}
@@ -2,10 +2,15 @@ package main
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"syscall"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"var/internal/config"
 	"var/internal/git"
 	"var/internal/ui"
 )
@@ -13,10 +18,25 @@ import (
 var version = "dev"
 
 func main() {
+	// --debug opens the log of git commands at startup
+	args := os.Args[1:]
+	debug := slices.Contains(args, "--debug")
+	args = slices.DeleteFunc(args, func(arg string) bool { return arg == "--debug" })
+
+	// Review a patch instead of a repository
+	if len(args) > 0 && args[0] == "--patch" {
+		patchFile := "-"
+		if len(args) > 1 {
+			patchFile = args[1]
+		}
+		reviewPatch(patchFile)
+		return
+	}
+
 	// Parse optional path argument
 	repoPath := "."
-	if len(os.Args) > 1 {
-		repoPath = os.Args[1]
+	if len(args) > 0 {
+		repoPath = args[0]
 	}
 
 	// Resolve to absolute path
@@ -37,20 +57,83 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Validate it's a git repository
-	if !git.IsGitRepository(absPath) {
-		fmt.Fprintf(os.Stderr, "Error: %s is not a git repository\n", absPath)
+	// Load user config
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 
 	// Initialize services
-	gitService := git.NewService(absPath)
+	gitService, err := git.NewService(absPath, cfg.GitPath, cfg.GitArgs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Validate it's a git repository
+	if !gitService.IsRepository() {
+		fmt.Fprintf(os.Stderr, "Error: %s is not a git repository\n", absPath)
+		os.Exit(1)
+	}
 
 	// Create and run the program
-	model := ui.NewModel(gitService)
+	model := ui.NewModel(gitService, cfg)
+	if debug {
+		model.OpenCommandLog()
+	}
 	p := tea.NewProgram(model, tea.WithAltScreen())
 
-	if _, err := p.Run(); err != nil {
+	// Bubbletea restores the terminal on its own, but git children spawned by
+	// in-flight commands would outlive it; stop them before quitting
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		<-sigs
+		gitService.Close()
+		p.Quit()
+	}()
+
+	// Save the terminal title on the title stack, to put it back once var quits
+	if cfg.WindowTitle {
+		fmt.Print("\x1b[22;0t")
+	}
+	_, err = p.Run()
+	if cfg.WindowTitle {
+		fmt.Print("\x1b[23;0t")
+	}
+	gitService.Close()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// reviewPatch shows a patch file, or stdin for "-", without needing a repository
+func reviewPatch(path string) {
+	var content []byte
+	var err error
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	if path == "-" {
+		content, err = io.ReadAll(os.Stdin)
+		// Stdin held the patch, so keys come from the terminal
+		opts = append(opts, tea.WithInputTTY())
+		path = "stdin"
+	} else {
+		content, err = os.ReadFile(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if _, err := tea.NewProgram(ui.NewPatchModel(path, string(content), cfg), opts...).Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}